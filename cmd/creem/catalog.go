@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/cloud-evan/gocreem/creem"
+	"github.com/cloud-evan/gocreem/creem/catalog"
+)
+
+func runCatalog(args []string) error {
+	if len(args) == 0 || (args[0] != "plan" && args[0] != "apply") {
+		return errors.New("missing subcommand, one of: plan, apply")
+	}
+	sub, args := args[0], args[1:]
+
+	fs := flag.NewFlagSet("catalog "+sub, flag.ExitOnError)
+	file := fs.String("f", "catalog.yaml", "目录文件，支持 .yaml/.yml/.json")
+	apiKey := fs.String("api-key", os.Getenv("CREEM_API_KEY"), "API密钥")
+	baseURL := fs.String("base-url", "", "API地址，默认 https://api.creem.io")
+	yes := fs.Bool("yes", false, "apply 时跳过确认")
+	_ = fs.Parse(args)
+
+	if *apiKey == "" {
+		return errors.New("missing api key, set --api-key or CREEM_API_KEY")
+	}
+	cat, err := catalog.Load(*file)
+	if err != nil {
+		return err
+	}
+
+	// 同步目录不校验Webhook签名，未配置密钥时使用占位值
+	secret := envSecret()
+	if secret == "" {
+		secret = "unused"
+	}
+	var options []creem.Option
+	if *baseURL != "" {
+		options = append(options, creem.WithProxyUrl(*baseURL))
+	}
+	client, err := creem.NewClient(*apiKey, secret, true, options...)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	plan, err := catalog.Diff(ctx, client, cat)
+	if err != nil {
+		return err
+	}
+	if err = plan.Write(os.Stdout); err != nil {
+		return err
+	}
+	if sub == "plan" || plan.Empty() {
+		return nil
+	}
+
+	if !*yes && !confirm("Apply these changes? [y/N] ") {
+		fmt.Println("Apply canceled.")
+		return nil
+	}
+	return catalog.Apply(ctx, client, plan, catalog.WithOutput(os.Stdout))
+}
+
+// confirm 从标准输入读取确认
+func confirm(prompt string) bool {
+	fmt.Print(prompt)
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
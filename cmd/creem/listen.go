@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/cloud-evan/gocreem/creem"
+	"github.com/cloud-evan/gocreem/pkg/xhttp"
+)
+
+// listenBodyLimit 接收的Webhook请求体大小上限
+const listenBodyLimit = 1 << 20
+
+func runListen(args []string) error {
+	fs := flag.NewFlagSet("listen", flag.ExitOnError)
+	addr := fs.String("addr", ":4242", "本地监听地址")
+	path := fs.String("path", "/", "接收Webhook的路径")
+	forwardTo := fs.String("forward-to", "", "转发目标，如 http://localhost:8080/webhook")
+	secret := fs.String("secret", envSecret(), "重新签名使用的密钥")
+	verifySecret := fs.String("verify-secret", "", "若设置，先用该密钥校验收到的签名")
+	_ = fs.Parse(args)
+
+	if *secret == "" {
+		return errors.New("missing secret, set --secret or CREEM_SECRET_KEY")
+	}
+
+	hc := xhttp.NewClient()
+	mux := http.NewServeMux()
+	mux.HandleFunc(*path, func(w http.ResponseWriter, r *http.Request) {
+		// 超过上限时返回 413，不截断后再校验签名
+		payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, listenBodyLimit))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				log.Printf("<-- rejected: payload exceeds %d bytes", tooLarge.Limit)
+				http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if *verifySecret != "" {
+			if err = creem.VerifyWebhookPayload(*verifySecret, payload, r.Header.Get(creem.HeaderSignature)); err != nil {
+				log.Printf("<-- rejected: %v", err)
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+		}
+		printEvent("<--", payload)
+
+		if *forwardTo == "" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		status, err := forwardEvent(r.Context(), hc, *forwardTo, *secret, payload)
+		if err != nil {
+			log.Printf("--> %s: %v", *forwardTo, err)
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(status)
+	})
+
+	srv := &http.Server{Addr: *addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("listening on %s%s", *addr, *path)
+	if *forwardTo != "" {
+		log.Printf("forwarding to %s", *forwardTo)
+	}
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// forwardEvent 使用密钥签名后将事件POST到目标地址，返回目标的状态码
+func forwardEvent(ctx context.Context, hc *xhttp.Client, url, secret string, payload []byte) (status int, err error) {
+	req := hc.Req()
+	req.Header.Set(creem.HeaderSignature, creem.SignWebhookPayload(secret, payload))
+
+	start := time.Now()
+	res, bs, err := req.Post(url).SendString(string(payload)).EndBytes(ctx)
+	if err != nil {
+		return 0, err
+	}
+	log.Printf("--> %s [%d] %s", url, res.StatusCode, time.Since(start).Round(time.Millisecond))
+	if len(bs) > 0 {
+		log.Printf("    %s", bytes.TrimSpace(bs))
+	}
+	return res.StatusCode, nil
+}
+
+// printEvent 打印事件摘要和格式化后的请求体
+func printEvent(prefix string, payload []byte) {
+	var event creem.WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil || event.EventType == "" {
+		log.Printf("%s (unrecognized payload) %s", prefix, payload)
+		return
+	}
+	log.Printf("%s %s %s", prefix, event.EventType, event.ID)
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, payload, "    ", "  "); err == nil {
+		fmt.Fprintf(os.Stdout, "    %s\n", buf.String())
+	}
+}
@@ -0,0 +1,57 @@
+// creem 命令行工具，用于本地调试Webhook和同步产品目录
+//
+//	creem listen --forward-to http://localhost:8080/webhook
+//	creem trigger checkout.completed --forward-to http://localhost:8080/webhook
+//	creem catalog plan -f catalog.yaml
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `Usage: creem <command> [flags]
+
+Commands:
+  listen   接收Webhook事件，打印并使用本地密钥重新签名后转发
+  trigger  构造签名的测试事件并发送到本地处理器
+  catalog  对比目录文件和线上产品、优惠码（plan），并执行变更（apply）
+
+Environment:
+  CREEM_SECRET_KEY  Webhook签名密钥（可用 --secret 覆盖）
+  CREEM_API_KEY     API密钥，catalog 使用（可用 --api-key 覆盖）
+
+Run "creem <command> -h" for command flags.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "listen":
+		err = runListen(args)
+	case "trigger":
+		err = runTrigger(args)
+	case "catalog":
+		err = runCatalog(args)
+	case "-h", "--help", "help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "creem: unknown command %q\n\n%s", cmd, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "creem %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+// envSecret 读取默认签名密钥
+func envSecret() string {
+	return os.Getenv("CREEM_SECRET_KEY")
+}
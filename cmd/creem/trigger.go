@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloud-evan/gocreem/creem"
+	"github.com/cloud-evan/gocreem/pkg/xhttp"
+)
+
+// eventFactory 根据事件类型构造事件对象
+type eventFactory func(f *triggerFixture) any
+
+// triggerFixture 构造测试事件所需的公共字段
+type triggerFixture struct {
+	ProductID  string
+	CustomerID string
+	Amount     float64
+	Currency   string
+	Now        time.Time
+}
+
+var eventFactories = map[string]eventFactory{
+	creem.EventCheckoutCompleted:    checkoutObject,
+	creem.EventSubscriptionActive:   subscriptionObject(creem.SubscriptionActive),
+	creem.EventSubscriptionPaid:     subscriptionObject(creem.SubscriptionActive),
+	creem.EventSubscriptionTrialing: subscriptionObject(creem.SubscriptionTrialing),
+	creem.EventSubscriptionUpdate:   subscriptionObject(creem.SubscriptionActive),
+	creem.EventSubscriptionPaused:   subscriptionObject(creem.SubscriptionPaused),
+	creem.EventSubscriptionCanceled: subscriptionObject(creem.SubscriptionCanceled),
+	creem.EventSubscriptionExpired:  subscriptionObject(creem.SubscriptionCanceled),
+	creem.EventRefundCreated:        refundObject,
+}
+
+func runTrigger(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("missing event type, one of: %s", strings.Join(eventTypes(), ", "))
+	}
+	eventType, args := args[0], args[1:]
+	factory, ok := eventFactories[eventType]
+	if !ok {
+		return fmt.Errorf("unsupported event type %q, one of: %s", eventType, strings.Join(eventTypes(), ", "))
+	}
+
+	fs := flag.NewFlagSet("trigger", flag.ExitOnError)
+	forwardTo := fs.String("forward-to", "", "本地处理器地址，如 http://localhost:8080/webhook")
+	secret := fs.String("secret", envSecret(), "签名使用的密钥")
+	productID := fs.String("product", "prod_"+randomID(), "事件中的产品ID")
+	customerID := fs.String("customer", "cust_"+randomID(), "事件中的客户ID")
+	amount := fs.Float64("amount", 29.99, "事件中的金额")
+	currency := fs.String("currency", creem.CurrencyUSD, "事件中的货币")
+	printOnly := fs.Bool("print", false, "只打印事件，不发送")
+	_ = fs.Parse(args)
+
+	if *secret == "" {
+		return errors.New("missing secret, set --secret or CREEM_SECRET_KEY")
+	}
+	if *forwardTo == "" && !*printOnly {
+		return errors.New("missing --forward-to")
+	}
+
+	fixture := &triggerFixture{
+		ProductID:  *productID,
+		CustomerID: *customerID,
+		Amount:     *amount,
+		Currency:   *currency,
+		Now:        time.Now().UTC(),
+	}
+	object, err := json.Marshal(factory(fixture))
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(&creem.WebhookEvent{
+		ID:        "evt_" + randomID(),
+		EventType: eventType,
+		CreatedAt: fixture.Now.UnixMilli(),
+		Object:    object,
+	})
+	if err != nil {
+		return err
+	}
+
+	printEvent("**", payload)
+	if *printOnly {
+		fmt.Printf("    %s: %s\n", creem.HeaderSignature, creem.SignWebhookPayload(*secret, payload))
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	status, err := forwardEvent(ctx, xhttp.NewClient(), *forwardTo, *secret, payload)
+	if err != nil {
+		return err
+	}
+	if status >= 300 {
+		return fmt.Errorf("handler responded with status %d", status)
+	}
+	return nil
+}
+
+func checkoutObject(f *triggerFixture) any {
+	return &creem.CheckoutSession{
+		ID:         "ch_" + randomID(),
+		ProductID:  f.ProductID,
+		CustomerID: f.CustomerID,
+		Status:     creem.StatusCompleted,
+		Amount:     f.Amount,
+		Currency:   f.Currency,
+		ReturnURL:  "https://example.com/return",
+		CancelURL:  "https://example.com/cancel",
+		SuccessURL: "https://example.com/success",
+		Metadata:   map[string]interface{}{"source": "creem-cli"},
+		CreatedAt:  f.Now.Add(-2 * time.Minute),
+		UpdatedAt:  f.Now,
+		ExpiresAt:  f.Now.Add(22 * time.Hour),
+	}
+}
+
+func subscriptionObject(status creem.SubscriptionStatus) eventFactory {
+	return func(f *triggerFixture) any {
+		sub := &creem.Subscription{
+			ID:                 "sub_" + randomID(),
+			CustomerID:         f.CustomerID,
+			ProductID:          f.ProductID,
+			Status:             status,
+			Amount:             f.Amount,
+			Currency:           f.Currency,
+			BillingCycle:       creem.BillingCycleMonthly,
+			CurrentPeriodStart: f.Now,
+			CurrentPeriodEnd:   f.Now.AddDate(0, 1, 0),
+			Metadata:           map[string]interface{}{"source": "creem-cli"},
+			CreatedAt:          f.Now,
+			UpdatedAt:          f.Now,
+		}
+		switch status {
+		case creem.SubscriptionTrialing:
+			sub.TrialDays = 14
+			sub.CurrentPeriodEnd = f.Now.AddDate(0, 0, 14)
+		case creem.SubscriptionCanceled:
+			canceledAt := f.Now
+			sub.CanceledAt = &canceledAt
+			sub.EndedAt = &canceledAt
+		}
+		return sub
+	}
+}
+
+func refundObject(f *triggerFixture) any {
+	return &creem.Refund{
+		ID:         "ref_" + randomID(),
+		OrderID:    "ord_" + randomID(),
+		CustomerID: f.CustomerID,
+		Amount:     f.Amount,
+		Currency:   f.Currency,
+		Status:     creem.RefundStatusSucceeded,
+		Reason:     "requested_by_customer",
+		CreatedAt:  f.Now,
+		UpdatedAt:  f.Now,
+	}
+}
+
+func eventTypes() []string {
+	types := make([]string, 0, len(eventFactories))
+	for k := range eventFactories {
+		types = append(types, k)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func randomID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
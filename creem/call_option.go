@@ -0,0 +1,100 @@
+package creem
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+// HeaderIdempotencyKey 幂等键请求头
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+// callOptions 单次调用的配置，优先于客户端配置
+type callOptions struct {
+	timeout time.Duration
+	headers http.Header
+	apiKey  string
+	baseURL string
+}
+
+// CallOption 单次调用选项，可传给任意 API 方法，不影响客户端配置
+type CallOption func(*callOptions)
+
+// WithTimeout 本次调用的超时时间，包含重试等待
+func WithTimeout(timeout time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithHeader 本次调用追加请求头，覆盖 SetRequestHeader 中的同名请求头
+func WithHeader(key, value string) CallOption {
+	return func(o *callOptions) {
+		if o.headers == nil {
+			o.headers = make(http.Header)
+		}
+		o.headers.Set(key, value)
+	}
+}
+
+// WithIdempotencyKey 设置幂等键，key 为空时每次调用自动生成新键，选项可重复使用。
+// 设置后 POST 请求在网络错误和 5xx 时也会按 RetryPolicy 重试，重试使用同一个幂等键
+func WithIdempotencyKey(key string) CallOption {
+	if key != "" {
+		return WithHeader(HeaderIdempotencyKey, key)
+	}
+	return func(o *callOptions) {
+		WithHeader(HeaderIdempotencyKey, newIdempotencyKey())(o)
+	}
+}
+
+// WithAPIKey 本次调用使用的 API 密钥，多租户场景下无需为每个租户创建客户端
+func WithAPIKey(apiKey string) CallOption {
+	return func(o *callOptions) {
+		o.apiKey = apiKey
+	}
+}
+
+// WithBaseURL 本次调用使用的基础URL
+func WithBaseURL(baseURL string) CallOption {
+	return func(o *callOptions) {
+		o.baseURL = baseURL
+	}
+}
+
+func newCallOptions(opts []CallOption) *callOptions {
+	o := new(callOptions)
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return o
+}
+
+// readOptions 方法内部附加的查询请求只沿用本次调用的 API 密钥和基础URL，
+// 不带幂等键和超时，避免与实际的写请求共用幂等键或重复计算超时
+func readOptions(opts []CallOption) []CallOption {
+	co := newCallOptions(opts)
+	var read []CallOption
+	if co.apiKey != "" {
+		read = append(read, WithAPIKey(co.apiKey))
+	}
+	if co.baseURL != "" {
+		read = append(read, WithBaseURL(co.baseURL))
+	}
+	return read
+}
+
+// idempotent 是否可以安全重试 POST
+func (o *callOptions) idempotent() bool {
+	return o.headers.Get(HeaderIdempotencyKey) != ""
+}
+
+// newIdempotencyKey 生成随机幂等键
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
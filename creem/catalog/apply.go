@@ -0,0 +1,223 @@
+package catalog
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/cloud-evan/gocreem"
+	"github.com/cloud-evan/gocreem/creem"
+)
+
+// Option Apply 的配置
+type Option func(*applier)
+
+// WithOutput 每完成一项变更输出一行进度
+func WithOutput(w io.Writer) Option {
+	return func(a *applier) {
+		a.out = w
+	}
+}
+
+type applier struct {
+	client *creem.Client
+	out    io.Writer
+}
+
+// Apply 按计划顺序执行变更，遇到错误立即停止。
+// 创建请求的幂等键由 key、内容以及被替换的ID计算，中断后重新 Diff、Apply 不会重复创建
+func Apply(ctx context.Context, client *creem.Client, plan *Plan, opts ...Option) error {
+	if client == nil {
+		return creem.MissCreemInitParamErr
+	}
+	if plan == nil {
+		return gocreem.MissParamErr
+	}
+	a := &applier{client: client}
+	for _, opt := range opts {
+		opt(a)
+	}
+	for i := range plan.Changes {
+		change := &plan.Changes[i]
+		var err error
+		switch change.Kind {
+		case KindProduct:
+			err = a.applyProduct(ctx, change)
+		case KindDiscountCode:
+			err = a.applyDiscountCode(ctx, change)
+		default:
+			err = fmt.Errorf("unknown kind %s", change.Kind)
+		}
+		if err != nil {
+			return fmt.Errorf("catalog: %s %s %s: %w", change.Action, change.Kind, change.Key, err)
+		}
+		if a.out != nil {
+			fmt.Fprintf(a.out, "%s %s %s: done\n", change.Action, change.Kind, change.Key)
+		}
+	}
+	return nil
+}
+
+func (a *applier) applyProduct(ctx context.Context, change *Change) error {
+	switch change.Action {
+	case ActionCreate:
+		p := change.product
+		req := &creem.ProductCreateRequest{
+			Name:        p.Name,
+			Description: p.Description,
+			Type:        p.Type,
+			Prices:      productPrices(p.Prices),
+			Active:      true,
+			Metadata:    managedMetadata(p.Key, p.Metadata),
+		}
+		req.Price, req.Currency = defaultPrice(p.Prices)
+		rsp, err := a.client.CreateProduct(ctx, req, creem.WithIdempotencyKey(idempotencyKey(change.Kind, p.Key, p)))
+		if err != nil {
+			return err
+		}
+		return checkCode(rsp.Code, rsp.Error)
+	case ActionUpdate:
+		p := change.product
+		// 发送完整的目标状态，价格列表整体替换
+		active := true
+		req := &creem.ProductUpdateRequest{
+			Name:        p.Name,
+			Description: p.Description,
+			Type:        p.Type,
+			Prices:      productPrices(p.Prices),
+			Active:      &active,
+			Metadata:    managedMetadata(p.Key, p.Metadata),
+		}
+		req.Price, req.Currency = defaultPrice(p.Prices)
+		rsp, err := a.client.UpdateProduct(ctx, change.ID, req)
+		if err != nil {
+			return err
+		}
+		return checkCode(rsp.Code, rsp.Error)
+	case ActionArchive:
+		// 停用产品，已有订阅不受影响
+		active := false
+		rsp, err := a.client.UpdateProduct(ctx, change.ID, &creem.ProductUpdateRequest{Active: &active})
+		if err != nil {
+			return err
+		}
+		return checkCode(rsp.Code, rsp.Error)
+	}
+	return fmt.Errorf("unsupported action %s", change.Action)
+}
+
+func (a *applier) applyDiscountCode(ctx context.Context, change *Change) error {
+	var req *creem.DiscountCodeCreateRequest
+	if change.Action == ActionCreate || change.Action == ActionReplace {
+		// 删除旧优惠码前先校验新内容，避免旧优惠码删除后新优惠码创建失败
+		var err error
+		if req, err = discountCodeRequest(change.discount, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	switch change.Action {
+	case ActionArchive, ActionReplace:
+		// 先删除旧优惠码，避免重建时 code 冲突
+		rsp, err := a.client.DeleteDiscountCode(ctx, change.ID)
+		if err != nil {
+			return err
+		}
+		// 404 表示已被删除，继续执行
+		if rsp.Code != http.StatusNotFound {
+			if err = checkCode(rsp.Code, rsp.Error); err != nil {
+				return err
+			}
+		}
+		if change.Action == ActionArchive {
+			return nil
+		}
+		fallthrough
+	case ActionCreate:
+		d := change.discount
+		// 重建时幂等键带上被替换的ID，内容改回旧版本时不会命中旧请求的缓存响应
+		idemKey := idempotencyKey(change.Kind, d.Key+"|"+change.ID, d)
+		rsp, err := a.client.CreateDiscountCode(ctx, req, creem.WithIdempotencyKey(idemKey))
+		if err == nil {
+			err = checkCode(rsp.Code, rsp.Error)
+		}
+		if err != nil && change.Action == ActionReplace {
+			return fmt.Errorf("old code %s deleted, create replacement (re-run Diff and Apply to retry): %w", change.ID, err)
+		}
+		return err
+	}
+	return fmt.Errorf("unsupported action %s", change.Action)
+}
+
+// discountCodeRequest 校验并生成创建请求，已过期的优惠码不会被创建
+func discountCodeRequest(d *DiscountCode, now time.Time) (*creem.DiscountCodeCreateRequest, error) {
+	if err := validateDiscountCode(d); err != nil {
+		return nil, err
+	}
+	if d.ValidUntil != nil && !d.ValidUntil.After(now) {
+		return nil, fmt.Errorf("valid_until %s is in the past", d.ValidUntil.Format(time.RFC3339))
+	}
+	req := &creem.DiscountCodeCreateRequest{
+		Code:     d.Code,
+		Type:     d.Type,
+		Value:    d.Value,
+		MaxUses:  d.MaxUses,
+		Metadata: managedMetadata(d.Key, d.Metadata),
+	}
+	if d.ValidFrom != nil {
+		req.ValidFrom = *d.ValidFrom
+	}
+	if d.ValidUntil != nil {
+		req.ValidUntil = *d.ValidUntil
+	}
+	return req, nil
+}
+
+func productPrices(prices []Price) []creem.ProductPrice {
+	list := make([]creem.ProductPrice, 0, len(prices))
+	for _, p := range prices {
+		list = append(list, creem.ProductPrice{Amount: p.Amount, Currency: p.Currency, BillingCycle: p.BillingCycle, Default: p.Default})
+	}
+	return list
+}
+
+// defaultPrice 兼容单价格字段，取默认价格，未指定时取第一个
+func defaultPrice(prices []Price) (float64, string) {
+	for _, p := range prices {
+		if p.Default {
+			return p.Amount, p.Currency
+		}
+	}
+	if len(prices) > 0 {
+		return prices[0].Amount, prices[0].Currency
+	}
+	return 0, ""
+}
+
+func managedMetadata(key string, metadata map[string]string) map[string]interface{} {
+	m := make(map[string]interface{}, len(metadata)+1)
+	for k, v := range metadata {
+		m[k] = v
+	}
+	m[MetadataKey] = key
+	return m
+}
+
+// idempotencyKey 相同内容的创建请求使用相同的幂等键，内容变化后生成新键
+func idempotencyKey(kind Kind, key string, spec any) string {
+	bs, _ := json.Marshal(spec)
+	sum := sha256.Sum256([]byte(string(kind) + "|" + key + "|" + string(bs)))
+	return "catalog-" + hex.EncodeToString(sum[:16])
+}
+
+func checkCode(code int, msg string) error {
+	if code != gocreem.Success {
+		return fmt.Errorf("status %d: %s", code, msg)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package catalog
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestApplyReplaceDiscountCode(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name     string
+		discount DiscountCode
+		requests []string
+		wantErr  bool
+	}{
+		{
+			name:     "valid replacement",
+			discount: DiscountCode{Key: "launch", Code: "LAUNCH30", Type: DiscountTypePercentage, Value: 30},
+			requests: []string{"DELETE /v1/discount-codes/dc_launch", "POST /v1/discount-codes"},
+		},
+		{
+			name:     "percentage over 100 keeps old code",
+			discount: DiscountCode{Key: "launch", Code: "LAUNCH30", Type: DiscountTypePercentage, Value: 130},
+			wantErr:  true,
+		},
+		{
+			name:     "unknown type keeps old code",
+			discount: DiscountCode{Key: "launch", Code: "LAUNCH30", Type: "percent", Value: 30},
+			wantErr:  true,
+		},
+		{
+			name:     "expired replacement keeps old code",
+			discount: DiscountCode{Key: "launch", Code: "LAUNCH30", Type: DiscountTypePercentage, Value: 30, ValidUntil: &past},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			client := testClient(t, "", "", &requests)
+			plan := &Plan{Changes: []Change{{Kind: KindDiscountCode, Action: ActionReplace, Key: "launch", ID: "dc_launch", discount: &tt.discount}}}
+
+			err := Apply(context.Background(), client, plan)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Apply error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if !slices.Equal(requests, tt.requests) {
+				t.Fatalf("requests = %q, want %q", requests, tt.requests)
+			}
+		})
+	}
+}
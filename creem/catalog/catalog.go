@@ -0,0 +1,179 @@
+// Package catalog 以声明式文件管理产品、价格和优惠码，文件可放在 git 中评审。
+// 每个资源以 key 作为稳定标识写入元数据 catalog_key，Diff 生成变更计划，Apply 幂等执行：
+//
+//	cat, err := catalog.Load("catalog.yaml")
+//	plan, err := catalog.Diff(ctx, client, cat)
+//	plan.Write(os.Stdout)
+//	err = catalog.Apply(ctx, client, plan)
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MetadataKey 保存稳定标识的元数据键，只有带该键的资源由 catalog 管理
+const MetadataKey = "catalog_key"
+
+// Catalog 产品目录文件
+//
+//	products:
+//	  - key: team
+//	    name: Team Plan
+//	    description: Team subscription plan
+//	    type: recurring
+//	    prices:
+//	      - {amount: 29.99, currency: USD, billing_cycle: monthly, default: true}
+//	discount_codes:
+//	  - key: launch
+//	    code: LAUNCH20
+//	    type: percentage
+//	    value: 20
+type Catalog struct {
+	Products      []Product      `json:"products" yaml:"products"`
+	DiscountCodes []DiscountCode `json:"discount_codes" yaml:"discount_codes"`
+}
+
+// Product 产品定义
+type Product struct {
+	Key         string            `json:"key" yaml:"key"`
+	Name        string            `json:"name" yaml:"name"`
+	Description string            `json:"description" yaml:"description"`
+	Type        string            `json:"type" yaml:"type"` // creem.ProductType*
+	Prices      []Price           `json:"prices" yaml:"prices"`
+	Metadata    map[string]string `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+}
+
+// Price 价格定义，一次性产品 BillingCycle 为空
+type Price struct {
+	Amount       float64 `json:"amount" yaml:"amount"`
+	Currency     string  `json:"currency" yaml:"currency"`
+	BillingCycle string  `json:"billing_cycle,omitempty" yaml:"billing_cycle,omitempty"`
+	Default      bool    `json:"default,omitempty" yaml:"default,omitempty"`
+}
+
+// 优惠码类型
+const (
+	DiscountTypePercentage  = "percentage"
+	DiscountTypeFixedAmount = "fixed_amount"
+)
+
+// DiscountCode 优惠码定义，Creem 不支持修改优惠码，内容变化时删除后重建
+type DiscountCode struct {
+	Key        string            `json:"key" yaml:"key"`
+	Code       string            `json:"code" yaml:"code"`
+	Type       string            `json:"type" yaml:"type"` // percentage, fixed_amount
+	Value      float64           `json:"value" yaml:"value"`
+	MaxUses    int               `json:"max_uses,omitempty" yaml:"max_uses,omitempty"`
+	ValidFrom  *time.Time        `json:"valid_from,omitempty" yaml:"valid_from,omitempty"`
+	ValidUntil *time.Time        `json:"valid_until,omitempty" yaml:"valid_until,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+}
+
+// Load 读取目录文件，按扩展名 .yaml/.yml/.json 解析
+func Load(path string) (*Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return nil, fmt.Errorf("catalog: unsupported file %s", path)
+	}
+	return Parse(data)
+}
+
+// Parse 解析 YAML 或 JSON 格式的目录
+func Parse(data []byte) (*Catalog, error) {
+	cat := new(Catalog)
+	// JSON 是 YAML 的子集，统一按 YAML 解析
+	if err := yaml.Unmarshal(data, cat); err != nil {
+		return nil, fmt.Errorf("catalog: parse: %w", err)
+	}
+	if err := cat.Validate(); err != nil {
+		return nil, err
+	}
+	return cat, nil
+}
+
+// Validate 校验 key 唯一以及必填字段
+func (c *Catalog) Validate() error {
+	keys := make(map[string]bool)
+	for _, p := range c.Products {
+		if err := checkKey("product", p.Key, p.Metadata, keys); err != nil {
+			return err
+		}
+		if p.Name == "" || p.Type == "" {
+			return fmt.Errorf("catalog: product %s: name and type are required", p.Key)
+		}
+		if len(p.Prices) == 0 {
+			return fmt.Errorf("catalog: product %s: at least one price is required", p.Key)
+		}
+		prices := make(map[string]bool)
+		for _, price := range p.Prices {
+			if price.Amount <= 0 || price.Currency == "" {
+				return fmt.Errorf("catalog: product %s: price amount and currency are required", p.Key)
+			}
+			k := strings.ToUpper(price.Currency) + "|" + price.BillingCycle
+			if prices[k] {
+				return fmt.Errorf("catalog: product %s: duplicate price %s %s", p.Key, price.Currency, price.BillingCycle)
+			}
+			prices[k] = true
+		}
+	}
+
+	keys = make(map[string]bool)
+	for _, d := range c.DiscountCodes {
+		if err := checkKey("discount code", d.Key, d.Metadata, keys); err != nil {
+			return err
+		}
+		if err := validateDiscountCode(&d); err != nil {
+			return fmt.Errorf("catalog: discount code %s: %w", d.Key, err)
+		}
+	}
+	return nil
+}
+
+// validateDiscountCode 校验优惠码内容，重建前也会调用，避免删除旧优惠码后才发现新内容无效
+func validateDiscountCode(d *DiscountCode) error {
+	if d.Code == "" || d.Type == "" || d.Value <= 0 {
+		return errors.New("code, type and positive value are required")
+	}
+	switch d.Type {
+	case DiscountTypePercentage:
+		if d.Value > 100 {
+			return fmt.Errorf("percentage value %v exceeds 100", d.Value)
+		}
+	case DiscountTypeFixedAmount:
+	default:
+		return fmt.Errorf("unsupported type %s", d.Type)
+	}
+	if d.MaxUses < 0 {
+		return errors.New("max_uses must not be negative")
+	}
+	if d.ValidFrom != nil && d.ValidUntil != nil && !d.ValidUntil.After(*d.ValidFrom) {
+		return errors.New("valid_until must be after valid_from")
+	}
+	return nil
+}
+
+func checkKey(kind, key string, metadata map[string]string, seen map[string]bool) error {
+	if key == "" {
+		return fmt.Errorf("catalog: %s without key", kind)
+	}
+	if seen[key] {
+		return fmt.Errorf("catalog: duplicate %s key %s", kind, key)
+	}
+	seen[key] = true
+	if _, ok := metadata[MetadataKey]; ok {
+		return fmt.Errorf("catalog: %s %s: metadata key %s is reserved", kind, key, MetadataKey)
+	}
+	return nil
+}
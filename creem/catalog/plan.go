@@ -0,0 +1,360 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloud-evan/gocreem"
+	"github.com/cloud-evan/gocreem/creem"
+)
+
+const listPageSize = 100
+
+// Kind 资源类型
+type Kind string
+
+const (
+	KindProduct      Kind = "product"
+	KindDiscountCode Kind = "discount_code"
+)
+
+// Action 变更类型
+type Action string
+
+const (
+	ActionCreate  Action = "create"
+	ActionUpdate  Action = "update"
+	ActionReplace Action = "replace" // 删除后重建，用于不支持修改的优惠码
+	ActionArchive Action = "archive" // 产品停用，优惠码删除
+)
+
+// Change 一项变更
+type Change struct {
+	Kind   Kind
+	Action Action
+	Key    string
+	ID     string   // 线上资源ID，创建时为空
+	Name   string   // 产品名或优惠码
+	Fields []string // 更新或重建时发生变化的字段
+
+	product  *Product
+	discount *DiscountCode
+}
+
+// Plan 变更计划，按产品、优惠码的顺序执行
+type Plan struct {
+	Changes []Change
+}
+
+// Empty 线上与文件一致
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Count 各类变更数量
+func (p *Plan) Count(action Action) int {
+	n := 0
+	for _, c := range p.Changes {
+		if c.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// Write 以可读格式输出计划
+func (p *Plan) Write(w io.Writer) error {
+	if p.Empty() {
+		_, err := fmt.Fprintln(w, "No changes. Catalog is up to date.")
+		return err
+	}
+	for _, c := range p.Changes {
+		var line string
+		switch c.Action {
+		case ActionCreate:
+			line = fmt.Sprintf("  + %s %s (%s)", c.Kind, c.Key, c.Name)
+		case ActionUpdate:
+			line = fmt.Sprintf("  ~ %s %s (%s) %s: %s", c.Kind, c.Key, c.Name, c.ID, strings.Join(c.Fields, ", "))
+		case ActionReplace:
+			line = fmt.Sprintf("-/+ %s %s (%s) %s: %s", c.Kind, c.Key, c.Name, c.ID, strings.Join(c.Fields, ", "))
+		case ActionArchive:
+			line = fmt.Sprintf("  - %s %s (%s) %s", c.Kind, c.Key, c.Name, c.ID)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "\nPlan: %d to create, %d to update, %d to replace, %d to archive.\n",
+		p.Count(ActionCreate), p.Count(ActionUpdate), p.Count(ActionReplace), p.Count(ActionArchive))
+	return err
+}
+
+// Diff 对比目录文件和线上的产品、优惠码，生成变更计划。
+// 只处理元数据中带 catalog_key 的线上资源，其他资源不受影响
+func Diff(ctx context.Context, client *creem.Client, cat *Catalog) (*Plan, error) {
+	if client == nil {
+		return nil, creem.MissCreemInitParamErr
+	}
+	if cat == nil {
+		return nil, gocreem.MissParamErr
+	}
+
+	products, err := listProducts(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	discounts, err := listDiscountCodes(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := new(Plan)
+	for i := range cat.Products {
+		desired := &cat.Products[i]
+		current, ok := products[desired.Key]
+		delete(products, desired.Key)
+		if !ok {
+			plan.Changes = append(plan.Changes, Change{Kind: KindProduct, Action: ActionCreate, Key: desired.Key, Name: desired.Name, product: desired})
+			continue
+		}
+		if fields := diffProduct(desired, current); len(fields) > 0 {
+			plan.Changes = append(plan.Changes, Change{Kind: KindProduct, Action: ActionUpdate, Key: desired.Key, ID: current.ID, Name: desired.Name, Fields: fields, product: desired})
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(products)) {
+		if current := products[key]; !archived(current) {
+			plan.Changes = append(plan.Changes, Change{Kind: KindProduct, Action: ActionArchive, Key: key, ID: current.ID, Name: current.Name})
+		}
+	}
+
+	for i := range cat.DiscountCodes {
+		desired := &cat.DiscountCodes[i]
+		current, ok := discounts[desired.Key]
+		delete(discounts, desired.Key)
+		if !ok {
+			plan.Changes = append(plan.Changes, Change{Kind: KindDiscountCode, Action: ActionCreate, Key: desired.Key, Name: desired.Code, discount: desired})
+			continue
+		}
+		if fields := diffDiscountCode(desired, current); len(fields) > 0 {
+			plan.Changes = append(plan.Changes, Change{Kind: KindDiscountCode, Action: ActionReplace, Key: desired.Key, ID: current.ID, Name: desired.Code, Fields: fields, discount: desired})
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(discounts)) {
+		// 已删除或过期的优惠码仍可能出现在列表中，不再重复删除
+		if current := discounts[key]; current.Active {
+			plan.Changes = append(plan.Changes, Change{Kind: KindDiscountCode, Action: ActionArchive, Key: key, ID: current.ID, Name: current.Code})
+		}
+	}
+	return plan, nil
+}
+
+// listProducts 分页获取全部由 catalog 管理的产品，按 key 索引
+func listProducts(ctx context.Context, client *creem.Client) (map[string]*creem.Product, error) {
+	products := make(map[string]*creem.Product)
+	params := new(creem.ListParams)
+	params.Limit = listPageSize
+	for params.Page = 1; ; params.Page++ {
+		rsp, err := client.ListProducts(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		if rsp.Code != gocreem.Success {
+			return nil, fmt.Errorf("catalog: list products status %d: %s", rsp.Code, rsp.Error)
+		}
+		for i := range rsp.Data {
+			product := &rsp.Data[i]
+			key := metadataString(product.Metadata, MetadataKey)
+			if key == "" {
+				continue
+			}
+			if existing, ok := products[key]; ok {
+				return nil, fmt.Errorf("catalog: products %s and %s share key %s", existing.ID, product.ID, key)
+			}
+			products[key] = product
+		}
+		// 未返回 total_count 时以不满一页为结束
+		if len(rsp.Data) < params.Limit || (rsp.TotalCount > 0 && params.Page*params.Limit >= rsp.TotalCount) {
+			return products, nil
+		}
+	}
+}
+
+// listDiscountCodes 分页获取全部由 catalog 管理的优惠码，按 key 索引
+func listDiscountCodes(ctx context.Context, client *creem.Client) (map[string]*creem.DiscountCode, error) {
+	discounts := make(map[string]*creem.DiscountCode)
+	params := new(creem.ListParams)
+	params.Limit = listPageSize
+	for params.Page = 1; ; params.Page++ {
+		rsp, err := client.ListDiscountCodes(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		if rsp.Code != gocreem.Success {
+			return nil, fmt.Errorf("catalog: list discount codes status %d: %s", rsp.Code, rsp.Error)
+		}
+		for i := range rsp.Data {
+			discount := &rsp.Data[i]
+			key := metadataString(discount.Metadata, MetadataKey)
+			if key == "" {
+				continue
+			}
+			if existing, ok := discounts[key]; ok {
+				return nil, fmt.Errorf("catalog: discount codes %s and %s share key %s", existing.ID, discount.ID, key)
+			}
+			discounts[key] = discount
+		}
+		// 未返回 total_count 时以不满一页为结束
+		if len(rsp.Data) < params.Limit || (rsp.TotalCount > 0 && params.Page*params.Limit >= rsp.TotalCount) {
+			return discounts, nil
+		}
+	}
+}
+
+func archived(p *creem.Product) bool {
+	return !p.Active
+}
+
+// diffProduct 返回发生变化的字段
+func diffProduct(desired *Product, current *creem.Product) (fields []string) {
+	if desired.Name != current.Name {
+		fields = append(fields, "name")
+	}
+	if desired.Description != current.Description {
+		fields = append(fields, "description")
+	}
+	if desired.Type != current.Type {
+		fields = append(fields, "type")
+	}
+	if !pricesEqual(desired.Prices, current) {
+		fields = append(fields, "prices")
+	}
+	if !metadataEqual(desired.Metadata, current.Metadata) {
+		fields = append(fields, "metadata")
+	}
+	if archived(current) {
+		fields = append(fields, "active")
+	}
+	return fields
+}
+
+// diffDiscountCode 返回发生变化的字段
+func diffDiscountCode(desired *DiscountCode, current *creem.DiscountCode) (fields []string) {
+	if desired.Code != current.Code {
+		fields = append(fields, "code")
+	}
+	if desired.Type != current.Type {
+		fields = append(fields, "type")
+	}
+	// 百分比和金额都按两位小数比较
+	if creem.ToMinorUnits(desired.Value, creem.CurrencyUSD) != creem.ToMinorUnits(current.Value, creem.CurrencyUSD) {
+		fields = append(fields, "value")
+	}
+	if desired.MaxUses != current.MaxUses {
+		fields = append(fields, "max_uses")
+	}
+	if !timeEqual(desired.ValidFrom, current.ValidFrom) {
+		fields = append(fields, "valid_from")
+	}
+	if !timeEqual(desired.ValidUntil, current.ValidUntil) {
+		fields = append(fields, "valid_until")
+	}
+	if !metadataEqual(desired.Metadata, current.Metadata) {
+		fields = append(fields, "metadata")
+	}
+	if !current.Active {
+		fields = append(fields, "active")
+	}
+	return fields
+}
+
+// priceKey 用于比较的价格，金额为最小货币单位
+type priceKey struct {
+	currency     string
+	billingCycle string
+	amount       int64
+	isDefault    bool
+}
+
+// pricesEqual 比较价格列表，文件中未指定默认价格时不比较默认标记
+func pricesEqual(desired []Price, current *creem.Product) bool {
+	want, have := normalizePrices(desired), normalizeCurrentPrices(current)
+	if !slices.ContainsFunc(want, func(k priceKey) bool { return k.isDefault }) {
+		for i := range have {
+			have[i].isDefault = false
+		}
+	}
+	return slices.Equal(want, have)
+}
+
+func normalizePrices(prices []Price) []priceKey {
+	keys := make([]priceKey, 0, len(prices))
+	for _, p := range prices {
+		keys = append(keys, priceKey{
+			currency:     strings.ToUpper(p.Currency),
+			billingCycle: p.BillingCycle,
+			amount:       creem.ToMinorUnits(p.Amount, p.Currency),
+			isDefault:    p.Default,
+		})
+	}
+	sortPrices(keys)
+	return keys
+}
+
+func normalizeCurrentPrices(p *creem.Product) []priceKey {
+	prices := make([]Price, 0, len(p.Prices))
+	for _, price := range p.Prices {
+		prices = append(prices, Price{Amount: price.Amount, Currency: price.Currency, BillingCycle: price.BillingCycle, Default: price.Default})
+	}
+	if len(prices) == 0 && p.Currency != "" {
+		prices = append(prices, Price{Amount: p.Price, Currency: p.Currency, Default: true})
+	}
+	return normalizePrices(prices)
+}
+
+func sortPrices(keys []priceKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].currency != keys[j].currency {
+			return keys[i].currency < keys[j].currency
+		}
+		return keys[i].billingCycle < keys[j].billingCycle
+	})
+}
+
+// metadataEqual 比较文件中的元数据和线上元数据，忽略 catalog_key
+func metadataEqual(desired map[string]string, current map[string]interface{}) bool {
+	n := 0
+	for k := range current {
+		if k != MetadataKey {
+			n++
+		}
+	}
+	if n != len(desired) {
+		return false
+	}
+	for k, v := range desired {
+		if cv, ok := current[k]; !ok || fmt.Sprint(cv) != v {
+			return false
+		}
+	}
+	return true
+}
+
+func metadataString(metadata map[string]interface{}, key string) string {
+	if v, ok := metadata[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+func timeEqual(desired *time.Time, current time.Time) bool {
+	if desired == nil {
+		return current.IsZero()
+	}
+	return desired.Equal(current)
+}
@@ -0,0 +1,183 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/cloud-evan/gocreem/creem"
+)
+
+// testClient 返回列表接口固定响应的客户端，其他请求记录到 requests
+func testClient(t *testing.T, products, discounts string, requests *[]string) *creem.Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/products":
+			fmt.Fprintf(w, `{"data":[%s]}`, products)
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/discount-codes":
+			fmt.Fprintf(w, `{"data":[%s]}`, discounts)
+		default:
+			if requests != nil {
+				*requests = append(*requests, r.Method+" "+r.URL.Path)
+			}
+			switch {
+			case r.Method == http.MethodDelete:
+				w.WriteHeader(http.StatusNoContent)
+				return
+			case r.Method == http.MethodPost && r.URL.Path == "/v1/discount-codes":
+				w.WriteHeader(http.StatusCreated)
+			}
+			fmt.Fprint(w, `{"data":{}}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	client, err := creem.NewClient("key", "secret", true, creem.WithProxyUrl(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestDiff(t *testing.T) {
+	products := `{"id":"prod_team","name":"Team","type":"recurring","active":true,"metadata":{"catalog_key":"team"},
+		"prices":[{"amount":29.99,"currency":"USD","billing_cycle":"monthly"}]},
+		{"id":"prod_same","name":"Same","type":"onetime","active":true,"metadata":{"catalog_key":"same","tier":"1"},"price":10,"currency":"EUR"},
+		{"id":"prod_old","name":"Old","active":true,"metadata":{"catalog_key":"old"}},
+		{"id":"prod_gone","name":"Gone","active":false,"metadata":{"catalog_key":"gone"}},
+		{"id":"prod_manual","name":"Manual","active":true}`
+	discounts := `{"id":"dc_launch","code":"LAUNCH20","type":"percentage","value":20,"active":true,"metadata":{"catalog_key":"launch"}},
+		{"id":"dc_old","code":"OLD","type":"percentage","value":5,"active":true,"metadata":{"catalog_key":"old"}},
+		{"id":"dc_expired","code":"EXPIRED","type":"percentage","value":5,"active":false,"metadata":{"catalog_key":"expired"}}`
+	cat := &Catalog{
+		Products: []Product{
+			{Key: "team", Name: "Team", Type: "recurring", Prices: []Price{{Amount: 39.99, Currency: "usd", BillingCycle: "monthly"}}},
+			{Key: "same", Name: "Same", Type: "onetime", Prices: []Price{{Amount: 10, Currency: "EUR"}}, Metadata: map[string]string{"tier": "1"}},
+			{Key: "new", Name: "New", Type: "onetime", Prices: []Price{{Amount: 5, Currency: "USD"}}},
+		},
+		DiscountCodes: []DiscountCode{
+			{Key: "launch", Code: "LAUNCH30", Type: DiscountTypePercentage, Value: 30},
+		},
+	}
+
+	plan, err := Diff(context.Background(), testClient(t, products, discounts, nil), cat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range plan.Changes {
+		got = append(got, fmt.Sprintf("%s %s %s %s %v", c.Action, c.Kind, c.Key, c.ID, c.Fields))
+	}
+	want := []string{
+		"update product team prod_team [prices]",
+		"create product new  []",
+		"archive product old prod_old []",
+		"replace discount_code launch dc_launch [code value]",
+		"archive discount_code old dc_old []",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("unexpected plan:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestDiffDuplicateKey(t *testing.T) {
+	products := `{"id":"prod_a","active":true,"metadata":{"catalog_key":"team"}},{"id":"prod_b","active":true,"metadata":{"catalog_key":"team"}}`
+	if _, err := Diff(context.Background(), testClient(t, products, "", nil), &Catalog{}); err == nil {
+		t.Fatal("expected error for duplicate catalog key")
+	}
+}
+
+func TestPricesEqual(t *testing.T) {
+	tests := []struct {
+		name    string
+		desired []Price
+		current creem.Product
+		equal   bool
+	}{
+		{
+			name:    "same prices in different order and case",
+			desired: []Price{{Amount: 10, Currency: "usd", BillingCycle: "monthly"}, {Amount: 9, Currency: "EUR", BillingCycle: "monthly"}},
+			current: creem.Product{Prices: []creem.ProductPrice{{Amount: 9, Currency: "EUR", BillingCycle: "monthly"}, {Amount: 10, Currency: "USD", BillingCycle: "monthly"}}},
+			equal:   true,
+		},
+		{
+			name:    "amount compared in minor units",
+			desired: []Price{{Amount: 29.99, Currency: "USD"}},
+			current: creem.Product{Prices: []creem.ProductPrice{{Amount: 29.990000001, Currency: "USD"}}},
+			equal:   true,
+		},
+		{
+			name:    "amount changed",
+			desired: []Price{{Amount: 29.99, Currency: "USD"}},
+			current: creem.Product{Prices: []creem.ProductPrice{{Amount: 19.99, Currency: "USD"}}},
+		},
+		{
+			name:    "billing cycle changed",
+			desired: []Price{{Amount: 10, Currency: "USD", BillingCycle: "yearly"}},
+			current: creem.Product{Prices: []creem.ProductPrice{{Amount: 10, Currency: "USD", BillingCycle: "monthly"}}},
+		},
+		{
+			name:    "price added",
+			desired: []Price{{Amount: 10, Currency: "USD"}, {Amount: 9, Currency: "EUR"}},
+			current: creem.Product{Prices: []creem.ProductPrice{{Amount: 10, Currency: "USD"}}},
+		},
+		{
+			name:    "default ignored when not specified",
+			desired: []Price{{Amount: 10, Currency: "USD"}},
+			current: creem.Product{Prices: []creem.ProductPrice{{Amount: 10, Currency: "USD", Default: true}}},
+			equal:   true,
+		},
+		{
+			name:    "default compared when specified",
+			desired: []Price{{Amount: 10, Currency: "USD", Default: true}, {Amount: 9, Currency: "EUR"}},
+			current: creem.Product{Prices: []creem.ProductPrice{{Amount: 10, Currency: "USD"}, {Amount: 9, Currency: "EUR", Default: true}}},
+		},
+		{
+			name:    "single price fields",
+			desired: []Price{{Amount: 10, Currency: "EUR", Default: true}},
+			current: creem.Product{Price: 10, Currency: "EUR"},
+			equal:   true,
+		},
+		{
+			name:    "zero decimal currency",
+			desired: []Price{{Amount: 1000, Currency: "JPY"}},
+			current: creem.Product{Prices: []creem.ProductPrice{{Amount: 1000.4, Currency: "JPY"}}},
+			equal:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pricesEqual(tt.desired, &tt.current); got != tt.equal {
+				t.Fatalf("pricesEqual = %t, want %t", got, tt.equal)
+			}
+		})
+	}
+}
+
+func TestMetadataEqual(t *testing.T) {
+	tests := []struct {
+		name    string
+		desired map[string]string
+		current map[string]interface{}
+		equal   bool
+	}{
+		{name: "both empty", equal: true},
+		{name: "catalog key ignored", current: map[string]interface{}{MetadataKey: "team"}, equal: true},
+		{name: "same values", desired: map[string]string{"tier": "pro"}, current: map[string]interface{}{"tier": "pro", MetadataKey: "team"}, equal: true},
+		{name: "numbers compared as strings", desired: map[string]string{"seats": "5"}, current: map[string]interface{}{"seats": float64(5)}, equal: true},
+		{name: "value changed", desired: map[string]string{"tier": "pro"}, current: map[string]interface{}{"tier": "basic"}},
+		{name: "key added", desired: map[string]string{"tier": "pro", "seats": "5"}, current: map[string]interface{}{"tier": "pro"}},
+		{name: "key removed", desired: map[string]string{"tier": "pro"}, current: map[string]interface{}{"tier": "pro", "seats": "5"}},
+		{name: "key renamed", desired: map[string]string{"plan": "pro"}, current: map[string]interface{}{"tier": "pro"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := metadataEqual(tt.desired, tt.current); got != tt.equal {
+				t.Fatalf("metadataEqual = %t, want %t", got, tt.equal)
+			}
+		})
+	}
+}
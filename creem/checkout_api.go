@@ -12,7 +12,7 @@ import (
 
 // CreateCheckoutSession 创建结账会话
 // 文档：https://docs.creem.io/api-reference/checkout#create-checkout-session
-func (c *Client) CreateCheckoutSession(ctx context.Context, req *CheckoutSessionCreateRequest) (rsp *CheckoutSessionResponse, err error) {
+func (c *Client) CreateCheckoutSession(ctx context.Context, req *CheckoutSessionCreateRequest, opts ...CallOption) (rsp *CheckoutSessionResponse, err error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
@@ -30,22 +30,26 @@ func (c *Client) CreateCheckoutSession(ctx context.Context, req *CheckoutSession
 	if req.SuccessURL == "" {
 		return nil, MissSuccessUrlErr
 	}
-
-	res, bs, err := c.doCreemPost(ctx, req, checkoutSessionCreate)
-	if err != nil {
+	if err = validateCheckoutRequest(req); err != nil {
 		return nil, err
 	}
 
-	rsp = &CheckoutSessionResponse{BaseResponse: BaseResponse{Code: gocreem.Success}}
-	if err = json.Unmarshal(bs, rsp); err != nil {
-		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gocreem.UnmarshalErr, err, string(bs))
+	meta, bs, err := c.doCreemPost(ctx, apiOp{Name: "CreateCheckoutSession"}, req, checkoutSessionCreate, opts...)
+	if err != nil {
+		return nil, err
 	}
 
-	if res.StatusCode != http.StatusCreated {
-		rsp.Code = res.StatusCode
+	rsp = &CheckoutSessionResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusCreated {
+		rsp.Code = meta.StatusCode
 		rsp.Error = string(bs)
 		rsp.ErrorResponse = new(ErrorResponse)
 		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, unmarshalResponseErr(meta, bs, err)
 	}
 
 	return rsp, nil
@@ -53,27 +57,28 @@ func (c *Client) CreateCheckoutSession(ctx context.Context, req *CheckoutSession
 
 // GetCheckoutSession 获取结账会话详情
 // 文档：https://docs.creem.io/api-reference/checkout#get-checkout-session
-func (c *Client) GetCheckoutSession(ctx context.Context, sessionID string) (rsp *CheckoutSessionResponse, err error) {
+func (c *Client) GetCheckoutSession(ctx context.Context, sessionID string, opts ...CallOption) (rsp *CheckoutSessionResponse, err error) {
 	if sessionID == "" {
 		return nil, MissCheckoutSessionIdErr
 	}
 
 	path := fmt.Sprintf(checkoutSessionDetail, sessionID)
-	res, bs, err := c.doCreemGet(ctx, path)
+	meta, bs, err := c.doCreemGet(ctx, apiOp{Name: "GetCheckoutSession", ResourceID: sessionID}, path, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	rsp = &CheckoutSessionResponse{BaseResponse: BaseResponse{Code: gocreem.Success}}
-	if err = json.Unmarshal(bs, rsp); err != nil {
-		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gocreem.UnmarshalErr, err, string(bs))
-	}
-
-	if res.StatusCode != http.StatusOK {
-		rsp.Code = res.StatusCode
+	rsp = &CheckoutSessionResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusOK {
+		rsp.Code = meta.StatusCode
 		rsp.Error = string(bs)
 		rsp.ErrorResponse = new(ErrorResponse)
 		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, unmarshalResponseErr(meta, bs, err)
 	}
 
 	return rsp, nil
@@ -0,0 +1,243 @@
+package creem
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/cloud-evan/gocreem"
+)
+
+// 构造器的元数据限制，为 SDK 的保守取值，不是 Creem 文档规定的限制，只在 Build 时校验
+const (
+	MetadataMaxKeys        = 50
+	MetadataMaxKeyLength   = 40
+	MetadataMaxValueLength = 500
+)
+
+// CheckoutBuilder 结账会话请求构造器，例如：
+//
+//	req, err := creem.NewCheckout("prod_123").
+//		CustomerEmail("user@example.com").
+//		Units(5).
+//		SuccessURL("https://example.com/success").
+//		MetadataString("order_ref", "A-1001").
+//		Build()
+type CheckoutBuilder struct {
+	req *CheckoutSessionCreateRequest
+	err error
+}
+
+// NewCheckout 初始化结账会话构造器
+func NewCheckout(productID string) *CheckoutBuilder {
+	return &CheckoutBuilder{req: &CheckoutSessionCreateRequest{ProductID: productID}}
+}
+
+// CustomerID 关联已有客户
+func (b *CheckoutBuilder) CustomerID(customerID string) *CheckoutBuilder {
+	b.req.CustomerID = customerID
+	return b
+}
+
+// CustomerEmail 预填客户邮箱
+func (b *CheckoutBuilder) CustomerEmail(email string) *CheckoutBuilder {
+	if b.req.Customer == nil {
+		b.req.Customer = new(CheckoutCustomer)
+	}
+	b.req.Customer.Email = email
+	return b
+}
+
+// DiscountCode 预填优惠码
+func (b *CheckoutBuilder) DiscountCode(code string) *CheckoutBuilder {
+	b.req.DiscountCode = code
+	return b
+}
+
+// Units 购买数量，如席位数
+func (b *CheckoutBuilder) Units(units int) *CheckoutBuilder {
+	b.req.Units = units
+	return b
+}
+
+// RequestID 业务侧请求ID，会回传到成功跳转URL和Webhook，用于关联本地订单
+func (b *CheckoutBuilder) RequestID(requestID string) *CheckoutBuilder {
+	b.req.RequestID = requestID
+	return b
+}
+
+// Amount 自定义金额，不设置时使用产品价格
+func (b *CheckoutBuilder) Amount(amount float64, currency string) *CheckoutBuilder {
+	b.req.Amount = amount
+	b.req.Currency = currency
+	return b
+}
+
+// PaymentMethodID 使用已保存的支付方式
+func (b *CheckoutBuilder) PaymentMethodID(paymentMethodID string) *CheckoutBuilder {
+	b.req.PaymentMethodID = paymentMethodID
+	return b
+}
+
+// SuccessURL 支付成功跳转地址
+func (b *CheckoutBuilder) SuccessURL(u string) *CheckoutBuilder {
+	b.req.SuccessURL = u
+	return b
+}
+
+// ReturnURL 返回地址
+func (b *CheckoutBuilder) ReturnURL(u string) *CheckoutBuilder {
+	b.req.ReturnURL = u
+	return b
+}
+
+// CancelURL 取消支付跳转地址
+func (b *CheckoutBuilder) CancelURL(u string) *CheckoutBuilder {
+	b.req.CancelURL = u
+	return b
+}
+
+// MetadataString 设置字符串元数据
+func (b *CheckoutBuilder) MetadataString(key, value string) *CheckoutBuilder {
+	return b.metadata(key, value)
+}
+
+// MetadataInt 设置整数元数据
+func (b *CheckoutBuilder) MetadataInt(key string, value int64) *CheckoutBuilder {
+	return b.metadata(key, value)
+}
+
+// MetadataFloat 设置浮点数元数据
+func (b *CheckoutBuilder) MetadataFloat(key string, value float64) *CheckoutBuilder {
+	return b.metadata(key, value)
+}
+
+// MetadataBool 设置布尔元数据
+func (b *CheckoutBuilder) MetadataBool(key string, value bool) *CheckoutBuilder {
+	return b.metadata(key, value)
+}
+
+// MetadataFrom 将结构体按 json 标签展开为元数据，字段只能是字符串、数字、布尔值
+func (b *CheckoutBuilder) MetadataFrom(v any) *CheckoutBuilder {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		b.setErr(fmt.Errorf("[%w]: %v", gocreem.MarshalErr, err))
+		return b
+	}
+	fields := make(map[string]any)
+	if err = json.Unmarshal(bs, &fields); err != nil {
+		b.setErr(fmt.Errorf("[%w]: metadata must be a struct or map: %v", InvalidMetadataErr, err))
+		return b
+	}
+	for key, value := range fields {
+		switch value.(type) {
+		case string, float64, bool:
+			b.metadata(key, value)
+		default:
+			b.setErr(fmt.Errorf("[%w]: key %q must be a string, number or bool", InvalidMetadataErr, key))
+		}
+	}
+	return b
+}
+
+// Build 校验并返回请求，可直接传给 CreateCheckoutSession
+func (b *CheckoutBuilder) Build() (*CheckoutSessionCreateRequest, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.req.ProductID == "" {
+		return nil, MissProductIdErr
+	}
+	if err := validateCheckoutRequest(b.req); err != nil {
+		return nil, err
+	}
+	if err := validateMetadata(b.req.Metadata); err != nil {
+		return nil, err
+	}
+	return b.req, nil
+}
+
+func (b *CheckoutBuilder) metadata(key string, value any) *CheckoutBuilder {
+	if b.req.Metadata == nil {
+		b.req.Metadata = make(map[string]interface{})
+	}
+	b.req.Metadata[key] = value
+	return b
+}
+
+func (b *CheckoutBuilder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// validateCheckoutRequest 校验URL、邮箱和数量
+func validateCheckoutRequest(req *CheckoutSessionCreateRequest) error {
+	urls := []struct{ name, value string }{
+		{"success_url", req.SuccessURL},
+		{"return_url", req.ReturnURL},
+		{"cancel_url", req.CancelURL},
+	}
+	for _, u := range urls {
+		if u.value == "" {
+			continue
+		}
+		if err := validateURL(u.value); err != nil {
+			return fmt.Errorf("%s: %w", u.name, err)
+		}
+	}
+	if req.Customer != nil && req.Customer.Email != "" {
+		if addr, err := mail.ParseAddress(req.Customer.Email); err != nil || addr.Address != req.Customer.Email {
+			return fmt.Errorf("[%w]: %s", InvalidEmailErr, req.Customer.Email)
+		}
+	}
+	if req.Units < 0 {
+		return fmt.Errorf("[%w]: units %d", InvalidQuantityErr, req.Units)
+	}
+	return nil
+}
+
+// validateURL 校验为绝对的 http(s) 地址
+func validateURL(u string) error {
+	parsed, err := url.Parse(u)
+	if err != nil || !parsed.IsAbs() || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return fmt.Errorf("[%w]: %s", InvalidUrlErr, u)
+	}
+	return nil
+}
+
+// validateMetadata 校验元数据的数量以及键、值的长度
+func validateMetadata(metadata map[string]interface{}) error {
+	if len(metadata) > MetadataMaxKeys {
+		return fmt.Errorf("[%w]: %d keys, at most %d", InvalidMetadataErr, len(metadata), MetadataMaxKeys)
+	}
+	for key, value := range metadata {
+		if key == "" {
+			return fmt.Errorf("[%w]: empty key", InvalidMetadataErr)
+		}
+		if n := utf8.RuneCountInString(key); n > MetadataMaxKeyLength {
+			return fmt.Errorf("[%w]: key %q has %d characters, at most %d", InvalidMetadataErr, key, n, MetadataMaxKeyLength)
+		}
+		var s string
+		switch v := value.(type) {
+		case nil:
+		case string:
+			s = v
+		case bool:
+			s = strconv.FormatBool(v)
+		default:
+			bs, err := json.Marshal(v)
+			if err != nil {
+				return fmt.Errorf("[%w]: key %q: %v", InvalidMetadataErr, key, err)
+			}
+			s = string(bs)
+		}
+		if n := utf8.RuneCountInString(s); n > MetadataMaxValueLength {
+			return fmt.Errorf("[%w]: value of %q has %d characters, at most %d", InvalidMetadataErr, key, n, MetadataMaxValueLength)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package creem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cloud-evan/gocreem"
+)
+
+const (
+	defaultWaitMinInterval = time.Second
+	defaultWaitMaxInterval = 10 * time.Second
+)
+
+// WaitOptions WaitForCheckout 的配置
+type WaitOptions struct {
+	MinInterval time.Duration               // 首次轮询间隔，默认1秒，之后翻倍
+	MaxInterval time.Duration               // 最大轮询间隔，默认10秒
+	Terminal    func(*CheckoutSession) bool // 是否为终态，默认 completed、failed、canceled、expired
+	Bus         *EventBus                   // 可选，收到该会话的 checkout.* 事件时立即结束等待或重新查询
+}
+
+// CheckoutResult 结账会话的最终状态
+type CheckoutResult struct {
+	Session      *CheckoutSession
+	Order        *Order        // 未生成订单时为空
+	Subscription *Subscription // 非订阅类产品时为空
+	FromEvent    bool          // true 表示结果来自 Webhook 事件
+}
+
+// Completed 是否支付完成
+func (r *CheckoutResult) Completed() bool {
+	return r.Session != nil && r.Session.Status == StatusCompleted
+}
+
+// CheckoutTerminal 默认的终态判断
+func CheckoutTerminal(session *CheckoutSession) bool {
+	switch session.Status {
+	case StatusCompleted, StatusFailed, StatusCanceled, StatusExpired:
+		return true
+	}
+	return false
+}
+
+// WaitForCheckout 轮询结账会话直到进入终态，轮询间隔指数退避，网络错误、限流和服务端错误时继续轮询。
+// 设置 opts.Bus 时收到该会话的 checkout.completed 等终态事件会立即结束，其他 checkout.* 事件会立即重新查询。
+// ctx 结束时返回 ctx.Err()，可用 context.WithTimeout 控制最长等待时间
+func (c *Client) WaitForCheckout(ctx context.Context, sessionID string, opts *WaitOptions, callOpts ...CallOption) (result *CheckoutResult, err error) {
+	if sessionID == "" {
+		return nil, MissCheckoutSessionIdErr
+	}
+	o := WaitOptions{MinInterval: defaultWaitMinInterval, MaxInterval: defaultWaitMaxInterval, Terminal: CheckoutTerminal}
+	if opts != nil {
+		if opts.MinInterval > 0 {
+			o.MinInterval = opts.MinInterval
+		}
+		if opts.MaxInterval > 0 {
+			o.MaxInterval = opts.MaxInterval
+		}
+		if opts.Terminal != nil {
+			o.Terminal = opts.Terminal
+		}
+		o.Bus = opts.Bus
+	}
+
+	var events <-chan *WebhookEvent
+	if o.Bus != nil {
+		var cancel func()
+		events, cancel = o.Bus.Subscribe(8)
+		defer cancel()
+	}
+
+	interval := o.MinInterval
+	for {
+		session, err := c.pollCheckout(ctx, sessionID, callOpts)
+		if err != nil {
+			return nil, err
+		}
+		if session != nil && o.Terminal(session) {
+			return newCheckoutResult(session, false), nil
+		}
+
+		// 无关事件不打断退避，匹配的终态事件立即返回，该会话的其他事件立即重新查询
+		timer := time.NewTimer(interval)
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+				break wait
+			case event := <-events:
+				session, related := matchCheckoutEvent(event, sessionID, o.Terminal)
+				if session != nil {
+					timer.Stop()
+					return newCheckoutResult(session, true), nil
+				}
+				if related {
+					timer.Stop()
+					break wait
+				}
+			}
+		}
+		interval = min(interval*2, o.MaxInterval)
+	}
+}
+
+// pollCheckout 查询一次结账会话，网络错误、限流和服务端错误时返回 nil 继续轮询
+func (c *Client) pollCheckout(ctx context.Context, sessionID string, opts []CallOption) (*CheckoutSession, error) {
+	rsp, err := c.GetCheckoutSession(ctx, sessionID, opts...)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// 响应无法解析时重试也不会成功
+		if errors.Is(err, gocreem.UnmarshalErr) {
+			return nil, err
+		}
+		return nil, nil
+	}
+	switch {
+	case rsp.Code == gocreem.Success:
+		return &rsp.Data, nil
+	case rsp.Code == http.StatusTooManyRequests || rsp.Code >= http.StatusInternalServerError:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("wait for checkout: get checkout session status %d: %s", rsp.Code, rsp.Error)
+	}
+}
+
+// matchCheckoutEvent 事件为该会话的 checkout.* 事件时 related 为 true，会话为终态时返回事件中的会话
+func matchCheckoutEvent(event *WebhookEvent, sessionID string, terminal func(*CheckoutSession) bool) (session *CheckoutSession, related bool) {
+	if event == nil || !strings.HasPrefix(event.EventType, "checkout.") {
+		return nil, false
+	}
+	session = new(CheckoutSession)
+	if err := json.Unmarshal(event.Object, session); err != nil || session.ID != sessionID {
+		return nil, false
+	}
+	if session.Status == "" && event.EventType == EventCheckoutCompleted {
+		session.Status = StatusCompleted
+	}
+	if session.Status == "" || !terminal(session) {
+		return nil, true
+	}
+	return session, true
+}
+
+func newCheckoutResult(session *CheckoutSession, fromEvent bool) *CheckoutResult {
+	return &CheckoutResult{
+		Session:      session,
+		Order:        session.Order,
+		Subscription: session.Subscription,
+		FromEvent:    fromEvent,
+	}
+}
@@ -0,0 +1,81 @@
+package creem
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitForCheckoutRetriesTransportErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			// 断开连接模拟网络错误
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				_ = conn.Close()
+			}
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			fmt.Fprint(w, `{"data":{"id":"cs_1","status":"completed"}}`)
+		}
+	}))
+	defer srv.Close()
+	client, err := NewClient("key", "secret", true, WithProxyUrl(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err := client.WaitForCheckout(ctx, "cs_1", &WaitOptions{MinInterval: time.Millisecond, MaxInterval: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Completed() || calls.Load() != 3 {
+		t.Fatalf("expected completed after 3 requests, got %+v after %d", result.Session, calls.Load())
+	}
+}
+
+func TestMatchCheckoutEvent(t *testing.T) {
+	tests := []struct {
+		name      string
+		eventType string
+		object    map[string]string
+		status    string
+		related   bool
+	}{
+		{name: "completed without status", eventType: EventCheckoutCompleted, object: map[string]string{"id": "cs_1"}, status: StatusCompleted, related: true},
+		{name: "expired", eventType: "checkout.expired", object: map[string]string{"id": "cs_1", "status": StatusExpired}, status: StatusExpired, related: true},
+		{name: "failed", eventType: "checkout.failed", object: map[string]string{"id": "cs_1", "status": StatusFailed}, status: StatusFailed, related: true},
+		{name: "non terminal triggers poll", eventType: "checkout.updated", object: map[string]string{"id": "cs_1", "status": StatusPending}, related: true},
+		{name: "other session", eventType: EventCheckoutCompleted, object: map[string]string{"id": "cs_2"}},
+		{name: "other event", eventType: EventRefundCreated, object: map[string]string{"id": "cs_1", "status": StatusCompleted}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			object, err := json.Marshal(tt.object)
+			if err != nil {
+				t.Fatal(err)
+			}
+			session, related := matchCheckoutEvent(&WebhookEvent{EventType: tt.eventType, Object: object}, "cs_1", CheckoutTerminal)
+			if related != tt.related {
+				t.Errorf("related = %t, want %t", related, tt.related)
+			}
+			var status string
+			if session != nil {
+				status = session.Status
+			}
+			if status != tt.status {
+				t.Errorf("status = %q, want %q", status, tt.status)
+			}
+		})
+	}
+}
@@ -2,25 +2,31 @@ package creem
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/cloud-evan/gocreem"
 	"github.com/cloud-evan/gocreem/pkg/xhttp"
 	"github.com/go-pay/xlog"
 )
 
-// Client Creem支付客户端
+// Client Creem支付客户端，可并发使用
+// ApiKey、SecretKey、DebugSwitch 请在并发使用前设置，其余配置通过 Set* 方法或 With 修改
 type Client struct {
-	ApiKey       string
-	SecretKey    string
-	IsProd       bool
-	DebugSwitch  gocreem.DebugSwitch
-	logger       xlog.XLogger
-	hc           *xhttp.Client
-	baseUrlProd  string
-	headerKeyMap map[string]string
+	ApiKey      string
+	SecretKey   string
+	IsProd      bool
+	DebugSwitch gocreem.DebugSwitch
+
+	mu      sync.Mutex // 串行化 Set* 方法
+	conf    atomic.Pointer[clientConfig]
+	pending *clientConfig // 仅在 NewClient、With 应用 Option 期间有效
 }
 
 type Option func(*Client)
@@ -38,19 +44,19 @@ func NewClient(apiKey, secretKey string, isProd bool, options ...Option) (client
 	logger.SetLevel(xlog.DebugLevel)
 
 	client = &Client{
-		ApiKey:       apiKey,
-		SecretKey:    secretKey,
-		IsProd:       isProd,
-		DebugSwitch:  gocreem.DebugOff,
-		logger:       logger,
-		hc:           xhttp.NewClient(),
-		baseUrlProd:  baseUrlProd,
-		headerKeyMap: make(map[string]string),
-	}
-
-	for _, option := range options {
-		option(client)
+		ApiKey:      apiKey,
+		SecretKey:   secretKey,
+		IsProd:      isProd,
+		DebugSwitch: gocreem.DebugOff,
+		pending: &clientConfig{
+			logger:       logger,
+			hc:           xhttp.NewClient(),
+			baseUrlProd:  baseUrlProd,
+			headerKeyMap: make(map[string]string),
+			redact:       newRedactor(DefaultRedactFields),
+		},
 	}
+	client.apply(options)
 
 	return client, nil
 }
@@ -58,204 +64,239 @@ func NewClient(apiKey, secretKey string, isProd bool, options ...Option) (client
 // WithProxyUrl 设置代理 URL
 func WithProxyUrl(proxyUrlProd string) Option {
 	return func(c *Client) {
-		c.baseUrlProd = proxyUrlProd
+		c.pending.baseUrlProd = proxyUrlProd
 	}
 }
 
 // WithHttpClient 设置自定义的xhttp.Client
 func WithHttpClient(client *xhttp.Client) Option {
 	return func(c *Client) {
-		c.hc = client
+		if client != nil {
+			c.pending.hc = client
+		}
 	}
 }
 
-// SetBodySize 设置http response body size(MB)
+// SetBodySize 设置http response body size(MB)，
+// 只影响当前客户端，共享的 xhttp.Client 不会被修改
 func (c *Client) SetBodySize(sizeMB int) {
 	if sizeMB > 0 {
-		c.hc.SetBodySize(sizeMB)
+		c.update(func(cfg *clientConfig) {
+			cfg.hc = cfg.hc.Clone().SetBodySize(sizeMB)
+		})
 	}
 }
 
 // SetHttpClient 设置自定义的xhttp.Client
 func (c *Client) SetHttpClient(client *xhttp.Client) {
 	if client != nil {
-		c.hc = client
+		c.update(func(cfg *clientConfig) {
+			cfg.hc = client
+		})
 	}
 }
 
 // SetLogger 设置自定义的logger
 func (c *Client) SetLogger(logger xlog.XLogger) {
 	if logger != nil {
-		c.logger = logger
+		c.update(func(cfg *clientConfig) {
+			cfg.logger = logger
+		})
 	}
 }
 
 // SetProxyUrl 设置代理 URL
 func (c *Client) SetProxyUrl(proxyUrlProd string) {
-	c.baseUrlProd = proxyUrlProd
+	c.update(func(cfg *clientConfig) {
+		cfg.baseUrlProd = proxyUrlProd
+	})
 }
 
 // SetRequestHeader 设置自定义的header
 func (c *Client) SetRequestHeader(key string, defaultVal ...string) {
 	if key != "" {
-		if len(defaultVal) > 0 {
-			c.headerKeyMap[key] = defaultVal[0]
-		} else {
-			c.headerKeyMap[key] = ""
-		}
+		c.update(func(cfg *clientConfig) {
+			if len(defaultVal) > 0 {
+				cfg.headerKeyMap[key] = defaultVal[0]
+			} else {
+				cfg.headerKeyMap[key] = ""
+			}
+		})
 	}
 }
 
 // ClearRequestHeader 清理自定义的header
 func (c *Client) ClearRequestHeader() {
-	c.headerKeyMap = make(map[string]string)
+	c.update(func(cfg *clientConfig) {
+		cfg.headerKeyMap = make(map[string]string)
+	})
 }
 
 // GetBaseUrl 获取基础URL
 func (c *Client) GetBaseUrl() string {
-	return c.baseUrlProd
+	return c.config().baseUrlProd
 }
 
 // doCreemGet 发送GET请求到Creem API
-func (c *Client) doCreemGet(ctx context.Context, path string) (res *http.Response, bs []byte, err error) {
-	url := c.GetBaseUrl() + path
-
-	req := c.hc.Req()
-
-	// 设置认证头 - Creem使用x-api-key
-	req.Header.Set("x-api-key", c.ApiKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	// 设置自定义header
-	for k, v := range c.headerKeyMap {
-		req.Header.Set(k, v)
-	}
-
-	if c.DebugSwitch == gocreem.DebugOn {
-		c.logger.Debugf("Creem_Request: %s", url)
-	}
-
-	res, bs, err = req.Get(url).EndBytes(ctx)
-	if err != nil {
-		return nil, nil, fmt.Errorf("http.Do Error: %w", err)
-	}
-
-	if c.DebugSwitch == gocreem.DebugOn {
-		c.logger.Debugf("Creem_Response: %s", string(bs))
-	}
-
-	return res, bs, nil
+func (c *Client) doCreemGet(ctx context.Context, op apiOp, path string, opts ...CallOption) (meta *ResponseMeta, bs []byte, err error) {
+	return c.doCreem(ctx, http.MethodGet, op, nil, path, nil, opts)
 }
 
 // doCreemPost 发送POST请求到Creem API
-func (c *Client) doCreemPost(ctx context.Context, body interface{}, path string) (res *http.Response, bs []byte, err error) {
-	url := c.GetBaseUrl() + path
+func (c *Client) doCreemPost(ctx context.Context, op apiOp, body interface{}, path string, opts ...CallOption) (meta *ResponseMeta, bs []byte, err error) {
+	return c.doCreem(ctx, http.MethodPost, op, body, path, nil, opts)
+}
 
-	req := c.hc.Req()
+// doCreemPut 发送PUT请求到Creem API
+func (c *Client) doCreemPut(ctx context.Context, op apiOp, body interface{}, path string, opts ...CallOption) (meta *ResponseMeta, bs []byte, err error) {
+	return c.doCreem(ctx, http.MethodPut, op, body, path, nil, opts)
+}
 
-	// 设置认证头 - Creem使用x-api-key
-	req.Header.Set("x-api-key", c.ApiKey)
-	req.Header.Set("Content-Type", "application/json")
+// doCreemDelete 发送DELETE请求到Creem API
+func (c *Client) doCreemDelete(ctx context.Context, op apiOp, path string, opts ...CallOption) (meta *ResponseMeta, bs []byte, err error) {
+	return c.doCreem(ctx, http.MethodDelete, op, nil, path, nil, opts)
+}
 
-	// 设置自定义header
-	for k, v := range c.headerKeyMap {
-		req.Header.Set(k, v)
-	}
+// doCreemGetStream 发送GET请求到Creem API，2xx 响应体交给 stream 逐步解码，不整体读入内存
+func (c *Client) doCreemGetStream(ctx context.Context, op apiOp, path string, stream func(body io.Reader) error, opts ...CallOption) (meta *ResponseMeta, bs []byte, err error) {
+	return c.doCreem(ctx, http.MethodGet, op, nil, path, stream, opts)
+}
 
-	if c.DebugSwitch == gocreem.DebugOn {
-		if body != nil {
-			bodyBytes, _ := json.Marshal(body)
-			c.logger.Debugf("Creem_Request: %s, Body: %s", url, string(bodyBytes))
-		} else {
-			c.logger.Debugf("Creem_Request: %s", url)
-		}
+// doCreem 发送请求到Creem API，前后调用已注册的 Hook，按 RetryPolicy 重试
+// stream 不为空时 2xx 响应体由 stream 解码，bs 为空；stream 返回的错误原样返回，不重试
+func (c *Client) doCreem(ctx context.Context, method string, op apiOp, body interface{}, path string, stream func(body io.Reader) error, opts []CallOption) (meta *ResponseMeta, bs []byte, err error) {
+	cfg := c.config()
+	co := newCallOptions(opts)
+	if co.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, co.timeout)
+		defer cancel()
 	}
-
-	if body != nil {
-		res, bs, err = req.Post(url).SendStruct(body).EndBytes(ctx)
-	} else {
-		res, bs, err = req.Post(url).EndBytes(ctx)
+	var res *http.Response
+	info := &RequestInfo{Operation: op.Name, Method: method, Path: path, ResourceID: op.ResourceID}
+	for _, hook := range cfg.hooks {
+		ctx = hook.BeforeRequest(ctx, info)
 	}
-
-	if err != nil {
-		return nil, nil, fmt.Errorf("http.Do Error: %w", err)
+	if cfg.metrics != nil {
+		cfg.metrics.RequestStarted(op.Name, method)
 	}
+	start := time.Now()
+	defer func() {
+		info.Duration = time.Since(start)
+		info.Err = err
+		if res != nil {
+			info.StatusCode = res.StatusCode
+		}
+		c.logResponse(ctx, cfg, info, bs)
+		if cfg.metrics != nil {
+			cfg.metrics.RequestFinished(op.Name, method, info.StatusCode, info.Duration, err)
+		}
+		for i := len(cfg.hooks) - 1; i >= 0; i-- {
+			cfg.hooks[i].AfterRequest(ctx, info)
+		}
+	}()
 
-	if c.DebugSwitch == gocreem.DebugOn {
-		c.logger.Debugf("Creem_Response: %s", string(bs))
-	}
-
-	return res, bs, nil
-}
-
-// doCreemPut 发送PUT请求到Creem API
-func (c *Client) doCreemPut(ctx context.Context, body interface{}, path string) (res *http.Response, bs []byte, err error) {
-	url := c.GetBaseUrl() + path
-
-	req := c.hc.Req()
-
-	// 设置认证头 - Creem使用x-api-key
-	req.Header.Set("x-api-key", c.ApiKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	// 设置自定义header
-	for k, v := range c.headerKeyMap {
-		req.Header.Set(k, v)
+	baseUrl := cfg.baseUrlProd
+	if co.baseURL != "" {
+		baseUrl = co.baseURL
 	}
-
-	if c.DebugSwitch == gocreem.DebugOn {
-		if body != nil {
-			bodyBytes, _ := json.Marshal(body)
-			c.logger.Debugf("Creem_Request: %s, Body: %s", url, string(bodyBytes))
-		} else {
-			c.logger.Debugf("Creem_Request: %s", url)
+	url := baseUrl + path
+	c.logRequest(ctx, cfg, co, info, url, body)
+
+	for attempt := 0; ; attempt++ {
+		res, bs, err = c.send(ctx, cfg, co, method, url, body, stream)
+		var se *streamError
+		if errors.As(err, &se) {
+			return cfg.newResponseMeta(res, info.Retries+1, time.Since(start)), nil, se.err
 		}
+		wait, rateLimited, retry := cfg.retry.retryWait(method, co.idempotent(), attempt, res, err)
+		if !retry || ctx.Err() != nil {
+			break
+		}
+		if cfg.metrics != nil {
+			if rateLimited {
+				cfg.metrics.RateLimitWaited(op.Name, wait)
+			}
+			cfg.metrics.RequestRetried(op.Name, method, attempt+1)
+		}
+		if c.DebugSwitch == gocreem.DebugOn {
+			cfg.logger.Debugf("Creem_Retry: %s %s, attempt: %d, wait: %s", method, url, attempt+1, wait)
+		}
+		if cfg.slogger != nil {
+			cfg.slogger.LogAttrs(ctx, slog.LevelDebug, "creem retry",
+				slog.String("operation", op.Name),
+				slog.String("method", method),
+				slog.String("path", path),
+				slog.Int("attempt", attempt+1),
+				slog.Duration("wait", wait),
+				slog.Bool("rate_limited", rateLimited))
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, nil, fmt.Errorf("http.Do Error: %w", ctx.Err())
+		case <-timer.C:
+		}
+		info.Retries++
 	}
-
-	if body != nil {
-		res, bs, err = req.Put(url).SendStruct(body).EndBytes(ctx)
-	} else {
-		res, bs, err = req.Put(url).EndBytes(ctx)
-	}
-
 	if err != nil {
 		return nil, nil, fmt.Errorf("http.Do Error: %w", err)
 	}
 
-	if c.DebugSwitch == gocreem.DebugOn {
-		c.logger.Debugf("Creem_Response: %s", string(bs))
-	}
+	return cfg.newResponseMeta(res, info.Retries+1, time.Since(start)), bs, nil
+}
 
-	return res, bs, nil
+// streamError 区分流式解码错误和网络错误
+type streamError struct {
+	err error
 }
 
-// doCreemDelete 发送DELETE请求到Creem API
-func (c *Client) doCreemDelete(ctx context.Context, path string) (res *http.Response, bs []byte, err error) {
-	url := c.GetBaseUrl() + path
+func (e *streamError) Error() string { return e.err.Error() }
 
-	req := c.hc.Req()
+func (e *streamError) Unwrap() error { return e.err }
+
+// send 发送一次请求
+func (c *Client) send(ctx context.Context, cfg *clientConfig, co *callOptions, method, url string, body interface{}, stream func(body io.Reader) error) (res *http.Response, bs []byte, err error) {
+	req := cfg.hc.Req()
 
 	// 设置认证头 - Creem使用x-api-key
-	req.Header.Set("x-api-key", c.ApiKey)
+	apiKey := c.currentAPIKey(cfg)
+	if co.apiKey != "" {
+		apiKey = co.apiKey
+	}
+	req.Header.Set("x-api-key", apiKey)
 	req.Header.Set("Content-Type", "application/json")
 
 	// 设置自定义header
-	for k, v := range c.headerKeyMap {
+	for k, v := range cfg.headerKeyMap {
 		req.Header.Set(k, v)
 	}
-
-	if c.DebugSwitch == gocreem.DebugOn {
-		c.logger.Debugf("Creem_Request: %s", url)
+	for k, v := range co.headers {
+		req.Header[k] = v
 	}
 
-	res, bs, err = req.Delete(url).EndBytes(ctx)
-	if err != nil {
-		return nil, nil, fmt.Errorf("http.Do Error: %w", err)
+	switch method {
+	case http.MethodPost:
+		req.Post(url)
+	case http.MethodPut:
+		req.Put(url)
+	case http.MethodDelete:
+		req.Delete(url)
+	default:
+		req.Get(url)
 	}
-
-	if c.DebugSwitch == gocreem.DebugOn {
-		c.logger.Debugf("Creem_Response: %s", string(bs))
+	if body != nil {
+		req.SendStruct(body)
 	}
 
-	return res, bs, nil
+	if stream == nil {
+		return req.EndBytes(ctx)
+	}
+	return req.EndStream(ctx, func(_ *http.Response, r io.Reader) error {
+		if err := stream(r); err != nil {
+			return &streamError{err: err}
+		}
+		return nil
+	})
 }
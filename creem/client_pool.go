@@ -0,0 +1,125 @@
+package creem
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloud-evan/gocreem/pkg/xhttp"
+)
+
+// ClientPool 按租户ID管理多个商户的客户端，首次使用时通过 CredentialProvider 创建，
+// 所有客户端共享同一个 xhttp.Client 连接池
+type ClientPool struct {
+	provider CredentialProvider
+	options  []Option
+
+	mu      sync.RWMutex
+	clients map[string]*Client
+}
+
+// NewClientPool 初始化客户端池
+// provider: 租户密钥来源
+// options: 应用到每个客户端的配置，如 WithHook、WithRetry；未设置 WithHttpClient 时自动共享一个连接池
+func NewClientPool(provider CredentialProvider, options ...Option) (*ClientPool, error) {
+	if provider == nil {
+		return nil, MissCredentialProviderErr
+	}
+	return &ClientPool{
+		provider: provider,
+		options:  append([]Option{WithHttpClient(xhttp.NewClient())}, options...),
+		clients:  make(map[string]*Client),
+	}, nil
+}
+
+// Get 获取租户的客户端，不存在时创建
+func (p *ClientPool) Get(ctx context.Context, tenantID string) (*Client, error) {
+	if tenantID == "" {
+		return nil, MissTenantIdErr
+	}
+	p.mu.RLock()
+	client, ok := p.clients[tenantID]
+	p.mu.RUnlock()
+	if ok {
+		return client, nil
+	}
+
+	creds, err := p.credentials(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	client, err = NewClient(creds.ApiKey, creds.SecretKey, true, p.options...)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// 并发创建时以先写入的为准
+	if existing, ok := p.clients[tenantID]; ok {
+		return existing, nil
+	}
+	p.clients[tenantID] = client
+	return client, nil
+}
+
+// Rotate 重新从 CredentialProvider 读取租户密钥并热更新到已创建的客户端，
+// overlap 时间内旧 SecretKey 签名的 Webhook 仍可通过校验。客户端尚未创建时无需处理
+func (p *ClientPool) Rotate(ctx context.Context, tenantID string, overlap time.Duration) error {
+	if tenantID == "" {
+		return MissTenantIdErr
+	}
+	p.mu.RLock()
+	client, ok := p.clients[tenantID]
+	p.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	creds, err := p.credentials(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if creds.ApiKey != client.CurrentAPIKey() {
+		if err = client.RotateAPIKey(creds.ApiKey); err != nil {
+			return err
+		}
+	}
+	if creds.SecretKey != client.CurrentSecretKey() {
+		if err = client.RotateSecretKey(creds.SecretKey, overlap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Remove 移除租户的客户端，下次 Get 时重新创建
+func (p *ClientPool) Remove(tenantID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.clients, tenantID)
+}
+
+// Tenants 已创建客户端的租户ID
+func (p *ClientPool) Tenants() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	ids := make([]string, 0, len(p.clients))
+	for id := range p.clients {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (p *ClientPool) credentials(ctx context.Context, tenantID string) (*Credentials, error) {
+	creds, err := p.provider.Credentials(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if creds == nil || creds.ApiKey == "" || creds.SecretKey == "" {
+		return nil, MissCredentialsErr
+	}
+	return creds, nil
+}
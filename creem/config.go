@@ -0,0 +1,80 @@
+package creem
+
+import (
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/cloud-evan/gocreem/pkg/xhttp"
+	"github.com/go-pay/xlog"
+)
+
+// clientConfig 客户端配置快照，发布后只读。
+// 请求开始时读取一次快照，Set* 方法复制后整体替换，因此并发调用和修改配置是安全的
+type clientConfig struct {
+	logger       xlog.XLogger
+	slogger      *slog.Logger
+	hc           *xhttp.Client
+	baseUrlProd  string
+	headerKeyMap map[string]string
+	redact       redactor
+	hooks        []Hook
+	metaHeaders  []string
+	metrics      MetricsCollector
+	retry        RetryPolicy
+
+	// 以下由 RotateAPIKey / RotateSecretKey 设置，为空时使用 Client.ApiKey / Client.SecretKey
+	apiKey          string
+	secretKey       string
+	prevSecretKey   string
+	prevSecretUntil time.Time
+}
+
+// clone 深拷贝可变字段，hc、logger 等共享
+func (cfg *clientConfig) clone() *clientConfig {
+	cp := *cfg
+	cp.headerKeyMap = maps.Clone(cfg.headerKeyMap)
+	cp.hooks = slices.Clip(cfg.hooks)
+	cp.metaHeaders = slices.Clip(cfg.metaHeaders)
+	return &cp
+}
+
+// config 当前配置快照
+func (c *Client) config() *clientConfig {
+	return c.conf.Load()
+}
+
+// update 复制当前配置，修改后发布新快照
+func (c *Client) update(fn func(cfg *clientConfig)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cfg := c.config().clone()
+	fn(cfg)
+	c.conf.Store(cfg)
+}
+
+// apply 在 pending 上应用 Option 并发布
+func (c *Client) apply(options []Option) {
+	for _, option := range options {
+		option(c)
+	}
+	c.conf.Store(c.pending)
+	c.pending = nil
+}
+
+// With 基于当前配置派生一个新客户端，共享 HTTP 连接池，
+// 对新客户端的修改不影响原客户端，例如：
+//
+//	tenant := client.With(creem.WithHook(tenantHook), creem.WithRetry(policy))
+func (c *Client) With(options ...Option) *Client {
+	derived := &Client{
+		ApiKey:      c.ApiKey,
+		SecretKey:   c.SecretKey,
+		IsProd:      c.IsProd,
+		DebugSwitch: c.DebugSwitch,
+		pending:     c.config().clone(),
+	}
+	derived.apply(options)
+	return derived
+}
@@ -1,8 +1,9 @@
 package creem
 
 const (
-	HeaderApiKey = "x-api-key"            // Creem API认证头
-	baseUrlProd  = "https://api.creem.io" // 正式 URL
+	HeaderApiKey    = "x-api-key"            // Creem API认证头
+	HeaderSignature = "creem-signature"      // Webhook签名头
+	baseUrlProd     = "https://api.creem.io" // 正式 URL
 
 	// Checkout相关
 	checkoutSessionCreate = "/v1/checkout-sessions"    // 创建结账会话 POST
@@ -12,6 +13,7 @@ const (
 	productCreate = "/v1/products"    // 创建产品 POST
 	productDetail = "/v1/products/%s" // product_id 获取产品 GET
 	productsList  = "/v1/products"    // 获取产品列表 GET
+	productUpdate = "/v1/products/%s" // product_id 更新产品 POST
 
 	// Customer相关
 	customerCreate = "/v1/customers"    // 创建客户 POST
@@ -35,12 +37,30 @@ const (
 	discountCodeCreate = "/v1/discount-codes"    // 创建优惠码 POST
 	discountCodeDelete = "/v1/discount-codes/%s" // discount_code_id 删除优惠码 DELETE
 	discountCodeDetail = "/v1/discount-codes/%s" // discount_code_id 获取优惠码 GET
+	discountCodesList  = "/v1/discount-codes"    // 获取优惠码列表 GET
 
 	// Subscription相关
 	subscriptionDetail  = "/v1/subscriptions/%s"         // subscription_id 获取订阅 GET
 	subscriptionUpdate  = "/v1/subscriptions/%s"         // subscription_id 更新订阅 POST
 	subscriptionUpgrade = "/v1/subscriptions/%s/upgrade" // subscription_id 升级订阅 POST
 	subscriptionCancel  = "/v1/subscriptions/%s/cancel"  // subscription_id 取消订阅 POST
+	subscriptionCreate  = "/v1/subscriptions"            // 创建订阅 POST
+	subscriptionsList   = "/v1/subscriptions"            // 获取订阅列表 GET
+	subscriptionPause   = "/v1/subscriptions/%s/pause"   // subscription_id 暂停订阅 POST
+	subscriptionResume  = "/v1/subscriptions/%s/resume"  // subscription_id 恢复订阅 POST
+	subscriptionUsage   = "/v1/subscriptions/%s/usage"   // subscription_id 上报用量 POST
+
+	// Webhook事件类型
+	EventCheckoutCompleted    = "checkout.completed"
+	EventSubscriptionActive   = "subscription.active"
+	EventSubscriptionPaid     = "subscription.paid"
+	EventSubscriptionCanceled = "subscription.canceled"
+	EventSubscriptionExpired  = "subscription.expired"
+	EventSubscriptionUpdate   = "subscription.update"
+	EventSubscriptionTrialing = "subscription.trialing"
+	EventSubscriptionPaused   = "subscription.paused"
+	EventRefundCreated        = "refund.created"
+	EventDisputeCreated       = "dispute.created"
 
 	// 状态常量
 	StatusActive    = "active"
@@ -50,6 +70,7 @@ const (
 	StatusCompleted = "completed"
 	StatusFailed    = "failed"
 	StatusRefunded  = "refunded"
+	StatusExpired   = "expired"
 	StatusPaused    = "paused"
 	StatusResumed   = "resumed"
 
@@ -60,13 +81,18 @@ const (
 	PaymentStatusCanceled  = "canceled"
 	PaymentStatusRefunded  = "refunded"
 
-	// 订阅状态
-	SubscriptionStatusActive   = "active"
-	SubscriptionStatusCanceled = "canceled"
-	SubscriptionStatusPaused   = "paused"
-	SubscriptionStatusPastDue  = "past_due"
-	SubscriptionStatusUnpaid   = "unpaid"
-	SubscriptionStatusTrialing = "trialing"
+	// 取消订阅方式
+	CancelModeImmediate   = "immediate" // 立即取消
+	CancelModeEndOfPeriod = "scheduled" // 当前计费周期结束时取消
+
+	// 修改数量、产品时的计费方式
+	UpdateBehaviorProrationChargeImmediately = "proration-charge-immediately" // 按比例计费并立即扣款
+	UpdateBehaviorProrationCharge            = "proration-charge"             // 按比例计费，下个账单周期扣款
+	UpdateBehaviorProrationNone              = "proration-none"               // 不按比例计费，下个周期按新数量计费
+
+	// 按比例计费的计算粒度
+	ProrationByDay    = "day"    // 按自然日
+	ProrationBySecond = "second" // 按秒
 
 	// 订单状态
 	OrderStatusPending   = "pending"
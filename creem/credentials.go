@@ -0,0 +1,91 @@
+package creem
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloud-evan/gocreem"
+)
+
+// Credentials 单个商户（租户）的密钥
+type Credentials struct {
+	ApiKey    string
+	SecretKey string
+}
+
+// CredentialProvider 按租户ID获取密钥，可对接配置中心、数据库或密钥管理服务
+type CredentialProvider interface {
+	Credentials(ctx context.Context, tenantID string) (*Credentials, error)
+}
+
+// CredentialProviderFunc 函数形式的 CredentialProvider
+type CredentialProviderFunc func(ctx context.Context, tenantID string) (*Credentials, error)
+
+func (f CredentialProviderFunc) Credentials(ctx context.Context, tenantID string) (*Credentials, error) {
+	return f(ctx, tenantID)
+}
+
+// StaticCredentialProvider 固定的租户密钥表
+type StaticCredentialProvider map[string]Credentials
+
+func (p StaticCredentialProvider) Credentials(_ context.Context, tenantID string) (*Credentials, error) {
+	creds, ok := p[tenantID]
+	if !ok {
+		return nil, TenantNotFoundErr
+	}
+	return &creds, nil
+}
+
+// RotateAPIKey 热更新 API 密钥，进行中的请求继续使用旧密钥
+func (c *Client) RotateAPIKey(apiKey string) error {
+	if apiKey == gocreem.NULL {
+		return gocreem.MissParamErr
+	}
+	c.update(func(cfg *clientConfig) {
+		cfg.apiKey = apiKey
+	})
+	return nil
+}
+
+// RotateSecretKey 热更新 Webhook 密钥，overlap 时间内旧密钥签名的 Webhook 仍可通过 VerifyWebhook
+func (c *Client) RotateSecretKey(secretKey string, overlap time.Duration) error {
+	if secretKey == gocreem.NULL {
+		return gocreem.MissParamErr
+	}
+	c.update(func(cfg *clientConfig) {
+		old := c.currentSecretKey(cfg)
+		cfg.secretKey = secretKey
+		if overlap > 0 && old != secretKey {
+			cfg.prevSecretKey = old
+			cfg.prevSecretUntil = time.Now().Add(overlap)
+		} else {
+			cfg.prevSecretKey = ""
+			cfg.prevSecretUntil = time.Time{}
+		}
+	})
+	return nil
+}
+
+// CurrentAPIKey 当前使用的 API 密钥，轮换后与 ApiKey 字段不同
+func (c *Client) CurrentAPIKey() string {
+	return c.currentAPIKey(c.config())
+}
+
+// CurrentSecretKey 当前使用的 Webhook 密钥，轮换后与 SecretKey 字段不同
+func (c *Client) CurrentSecretKey() string {
+	return c.currentSecretKey(c.config())
+}
+
+func (c *Client) currentAPIKey(cfg *clientConfig) string {
+	if cfg.apiKey != "" {
+		return cfg.apiKey
+	}
+	return c.ApiKey
+}
+
+func (c *Client) currentSecretKey(cfg *clientConfig) string {
+	if cfg.secretKey != "" {
+		return cfg.secretKey
+	}
+	return c.SecretKey
+}
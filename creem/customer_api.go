@@ -14,7 +14,7 @@ import (
 
 // CustomersList 获取客户列表
 // 文档：https://docs.creem.io/api/customers#list-customers
-func (c *Client) CustomersList(ctx context.Context, params *ListParams) (rsp *CustomersListResponse, err error) {
+func (c *Client) CustomersList(ctx context.Context, params *ListParams, opts ...CallOption) (rsp *CustomersListResponse, err error) {
 	if params == nil {
 		params = &ListParams{}
 	}
@@ -36,21 +36,22 @@ func (c *Client) CustomersList(ctx context.Context, params *ListParams) (rsp *Cu
 		path += "?" + queryParams.Encode()
 	}
 
-	res, bs, err := c.doCreemGet(ctx, path)
+	meta, bs, err := c.doCreemGet(ctx, apiOp{Name: "CustomersList"}, path, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	rsp = &CustomersListResponse{BaseResponse: BaseResponse{Code: gocreem.Success}}
-	if err = json.Unmarshal(bs, rsp); err != nil {
-		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gocreem.UnmarshalErr, err, string(bs))
-	}
-
-	if res.StatusCode != http.StatusOK {
-		rsp.Code = res.StatusCode
+	rsp = &CustomersListResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusOK {
+		rsp.Code = meta.StatusCode
 		rsp.Error = string(bs)
 		rsp.ErrorResponse = new(ErrorResponse)
 		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, unmarshalResponseErr(meta, bs, err)
 	}
 
 	return rsp, nil
@@ -58,27 +59,28 @@ func (c *Client) CustomersList(ctx context.Context, params *ListParams) (rsp *Cu
 
 // GetCustomer 获取客户详情
 // 文档：https://docs.creem.io/api-reference/customer#get-customer
-func (c *Client) GetCustomer(ctx context.Context, customerID string) (rsp *CustomerDetailResponse, err error) {
+func (c *Client) GetCustomer(ctx context.Context, customerID string, opts ...CallOption) (rsp *CustomerDetailResponse, err error) {
 	if customerID == "" {
 		return nil, MissCustomerIdErr
 	}
 
 	path := fmt.Sprintf(customerDetail, customerID)
-	res, bs, err := c.doCreemGet(ctx, path)
+	meta, bs, err := c.doCreemGet(ctx, apiOp{Name: "GetCustomer", ResourceID: customerID}, path, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	rsp = &CustomerDetailResponse{BaseResponse: BaseResponse{Code: gocreem.Success}}
-	if err = json.Unmarshal(bs, rsp); err != nil {
-		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gocreem.UnmarshalErr, err, string(bs))
-	}
-
-	if res.StatusCode != http.StatusOK {
-		rsp.Code = res.StatusCode
+	rsp = &CustomerDetailResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusOK {
+		rsp.Code = meta.StatusCode
 		rsp.Error = string(bs)
 		rsp.ErrorResponse = new(ErrorResponse)
 		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, unmarshalResponseErr(meta, bs, err)
 	}
 
 	return rsp, nil
@@ -86,7 +88,7 @@ func (c *Client) GetCustomer(ctx context.Context, customerID string) (rsp *Custo
 
 // CustomerCreate 创建客户
 // 文档：https://docs.creem.io/api/customers#create-customer
-func (c *Client) CustomerCreate(ctx context.Context, req *CustomerCreateRequest) (rsp *CustomerCreateResponse, err error) {
+func (c *Client) CustomerCreate(ctx context.Context, req *CustomerCreateRequest, opts ...CallOption) (rsp *CustomerCreateResponse, err error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
@@ -99,21 +101,22 @@ func (c *Client) CustomerCreate(ctx context.Context, req *CustomerCreateRequest)
 		return nil, MissNameErr
 	}
 
-	res, bs, err := c.doCreemPost(ctx, req, customerCreate)
+	meta, bs, err := c.doCreemPost(ctx, apiOp{Name: "CustomerCreate"}, req, customerCreate, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	rsp = &CustomerCreateResponse{BaseResponse: BaseResponse{Code: gocreem.Success}}
-	if err = json.Unmarshal(bs, rsp); err != nil {
-		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gocreem.UnmarshalErr, err, string(bs))
-	}
-
-	if res.StatusCode != http.StatusCreated {
-		rsp.Code = res.StatusCode
+	rsp = &CustomerCreateResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusCreated {
+		rsp.Code = meta.StatusCode
 		rsp.Error = string(bs)
 		rsp.ErrorResponse = new(ErrorResponse)
 		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, unmarshalResponseErr(meta, bs, err)
 	}
 
 	return rsp, nil
@@ -121,7 +124,7 @@ func (c *Client) CustomerCreate(ctx context.Context, req *CustomerCreateRequest)
 
 // CustomerUpdate 更新客户
 // 文档：https://docs.creem.io/api/customers#update-customer
-func (c *Client) CustomerUpdate(ctx context.Context, customerID string, req *CustomerUpdateRequest) (rsp *CustomerUpdateResponse, err error) {
+func (c *Client) CustomerUpdate(ctx context.Context, customerID string, req *CustomerUpdateRequest, opts ...CallOption) (rsp *CustomerUpdateResponse, err error) {
 	if customerID == "" {
 		return nil, MissCustomerIdErr
 	}
@@ -130,21 +133,22 @@ func (c *Client) CustomerUpdate(ctx context.Context, customerID string, req *Cus
 	}
 
 	path := fmt.Sprintf(customerUpdate, customerID)
-	res, bs, err := c.doCreemPut(ctx, req, path)
+	meta, bs, err := c.doCreemPut(ctx, apiOp{Name: "CustomerUpdate", ResourceID: customerID}, req, path, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	rsp = &CustomerUpdateResponse{BaseResponse: BaseResponse{Code: gocreem.Success}}
-	if err = json.Unmarshal(bs, rsp); err != nil {
-		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gocreem.UnmarshalErr, err, string(bs))
-	}
-
-	if res.StatusCode != http.StatusOK {
-		rsp.Code = res.StatusCode
+	rsp = &CustomerUpdateResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusOK {
+		rsp.Code = meta.StatusCode
 		rsp.Error = string(bs)
 		rsp.ErrorResponse = new(ErrorResponse)
 		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, unmarshalResponseErr(meta, bs, err)
 	}
 
 	return rsp, nil
@@ -152,20 +156,20 @@ func (c *Client) CustomerUpdate(ctx context.Context, customerID string, req *Cus
 
 // CustomerDelete 删除客户
 // 文档：https://docs.creem.io/api/customers#delete-customer
-func (c *Client) CustomerDelete(ctx context.Context, customerID string) (rsp *BaseResponse, err error) {
+func (c *Client) CustomerDelete(ctx context.Context, customerID string, opts ...CallOption) (rsp *BaseResponse, err error) {
 	if customerID == "" {
 		return nil, MissCustomerIdErr
 	}
 
 	path := fmt.Sprintf(customerDelete, customerID)
-	res, bs, err := c.doCreemDelete(ctx, path)
+	meta, bs, err := c.doCreemDelete(ctx, apiOp{Name: "CustomerDelete", ResourceID: customerID}, path, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	rsp = &BaseResponse{Code: gocreem.Success}
-	if res.StatusCode != http.StatusNoContent {
-		rsp.Code = res.StatusCode
+	rsp = &BaseResponse{Code: gocreem.Success, Meta: meta}
+	if meta.StatusCode != http.StatusNoContent {
+		rsp.Code = meta.StatusCode
 		rsp.Error = string(bs)
 		rsp.ErrorResponse = new(ErrorResponse)
 		_ = json.Unmarshal(bs, rsp.ErrorResponse)
@@ -176,7 +180,7 @@ func (c *Client) CustomerDelete(ctx context.Context, customerID string) (rsp *Ba
 
 // CustomerPortalCreate 创建客户门户会话
 // 文档：https://docs.creem.io/api/customer-portal#create-portal-session
-func (c *Client) CustomerPortalCreate(ctx context.Context, req *CustomerPortalCreateRequest) (rsp *CustomerPortalCreateResponse, err error) {
+func (c *Client) CustomerPortalCreate(ctx context.Context, req *CustomerPortalCreateRequest, opts ...CallOption) (rsp *CustomerPortalCreateResponse, err error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
@@ -189,21 +193,22 @@ func (c *Client) CustomerPortalCreate(ctx context.Context, req *CustomerPortalCr
 		return nil, MissReturnUrlErr
 	}
 
-	res, bs, err := c.doCreemPost(ctx, req, customerPortalCreate)
+	meta, bs, err := c.doCreemPost(ctx, apiOp{Name: "CustomerPortalCreate"}, req, customerPortalCreate, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	rsp = &CustomerPortalCreateResponse{BaseResponse: BaseResponse{Code: gocreem.Success}}
-	if err = json.Unmarshal(bs, rsp); err != nil {
-		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gocreem.UnmarshalErr, err, string(bs))
-	}
-
-	if res.StatusCode != http.StatusCreated {
-		rsp.Code = res.StatusCode
+	rsp = &CustomerPortalCreateResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusCreated {
+		rsp.Code = meta.StatusCode
 		rsp.Error = string(bs)
 		rsp.ErrorResponse = new(ErrorResponse)
 		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, unmarshalResponseErr(meta, bs, err)
 	}
 
 	return rsp, nil
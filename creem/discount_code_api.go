@@ -6,13 +6,15 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/cloud-evan/gocreem"
 )
 
 // CreateDiscountCode 创建优惠码
 // 文档：https://docs.creem.io/api-reference/discount-code#create-discount-code
-func (c *Client) CreateDiscountCode(ctx context.Context, req *DiscountCodeCreateRequest) (rsp *DiscountCodeCreateResponse, err error) {
+func (c *Client) CreateDiscountCode(ctx context.Context, req *DiscountCodeCreateRequest, opts ...CallOption) (rsp *DiscountCodeCreateResponse, err error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
@@ -28,21 +30,22 @@ func (c *Client) CreateDiscountCode(ctx context.Context, req *DiscountCodeCreate
 		return nil, errors.New("discount value must be greater than 0")
 	}
 
-	res, bs, err := c.doCreemPost(ctx, req, discountCodeCreate)
+	meta, bs, err := c.doCreemPost(ctx, apiOp{Name: "CreateDiscountCode"}, req, discountCodeCreate, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	rsp = &DiscountCodeCreateResponse{BaseResponse: BaseResponse{Code: gocreem.Success}}
-	if err = json.Unmarshal(bs, rsp); err != nil {
-		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gocreem.UnmarshalErr, err, string(bs))
-	}
-
-	if res.StatusCode != http.StatusCreated {
-		rsp.Code = res.StatusCode
+	rsp = &DiscountCodeCreateResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusCreated {
+		rsp.Code = meta.StatusCode
 		rsp.Error = string(bs)
 		rsp.ErrorResponse = new(ErrorResponse)
 		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, unmarshalResponseErr(meta, bs, err)
 	}
 
 	return rsp, nil
@@ -50,27 +53,76 @@ func (c *Client) CreateDiscountCode(ctx context.Context, req *DiscountCodeCreate
 
 // GetDiscountCode 获取优惠码详情
 // 文档：https://docs.creem.io/api-reference/discount-code#get-discount-code
-func (c *Client) GetDiscountCode(ctx context.Context, discountCodeID string) (rsp *DiscountCodeDetailResponse, err error) {
+func (c *Client) GetDiscountCode(ctx context.Context, discountCodeID string, opts ...CallOption) (rsp *DiscountCodeDetailResponse, err error) {
 	if discountCodeID == "" {
 		return nil, errors.New("discount code id is required")
 	}
 
 	path := fmt.Sprintf(discountCodeDetail, discountCodeID)
-	res, bs, err := c.doCreemGet(ctx, path)
+	meta, bs, err := c.doCreemGet(ctx, apiOp{Name: "GetDiscountCode", ResourceID: discountCodeID}, path, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	rsp = &DiscountCodeDetailResponse{BaseResponse: BaseResponse{Code: gocreem.Success}}
+	rsp = &DiscountCodeDetailResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusOK {
+		rsp.Code = meta.StatusCode
+		rsp.Error = string(bs)
+		rsp.ErrorResponse = new(ErrorResponse)
+		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
 	if err = json.Unmarshal(bs, rsp); err != nil {
-		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gocreem.UnmarshalErr, err, string(bs))
+		return nil, unmarshalResponseErr(meta, bs, err)
+	}
+
+	return rsp, nil
+}
+
+// ListDiscountCodes 获取优惠码列表
+// 文档：https://docs.creem.io/api-reference/discount-code#list-discount-codes
+func (c *Client) ListDiscountCodes(ctx context.Context, params *ListParams, opts ...CallOption) (rsp *DiscountCodesListResponse, err error) {
+	if params == nil {
+		params = &ListParams{}
+	}
+
+	// 构建查询参数
+	queryParams := url.Values{}
+	if params.Page > 0 {
+		queryParams.Set("page", strconv.Itoa(params.Page))
+	}
+	if params.Limit > 0 {
+		queryParams.Set("limit", strconv.Itoa(params.Limit))
+	}
+	if params.Status != "" {
+		queryParams.Set("status", params.Status)
+	}
+	if params.ProductID != "" {
+		queryParams.Set("product_id", params.ProductID)
+	}
+
+	path := discountCodesList
+	if len(queryParams) > 0 {
+		path += "?" + queryParams.Encode()
 	}
 
-	if res.StatusCode != http.StatusOK {
-		rsp.Code = res.StatusCode
+	meta, bs, err := c.doCreemGet(ctx, apiOp{Name: "ListDiscountCodes"}, path, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &DiscountCodesListResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusOK {
+		rsp.Code = meta.StatusCode
 		rsp.Error = string(bs)
 		rsp.ErrorResponse = new(ErrorResponse)
 		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, unmarshalResponseErr(meta, bs, err)
 	}
 
 	return rsp, nil
@@ -78,20 +130,20 @@ func (c *Client) GetDiscountCode(ctx context.Context, discountCodeID string) (rs
 
 // DeleteDiscountCode 删除优惠码
 // 文档：https://docs.creem.io/api-reference/discount-code#delete-discount-code
-func (c *Client) DeleteDiscountCode(ctx context.Context, discountCodeID string) (rsp *BaseResponse, err error) {
+func (c *Client) DeleteDiscountCode(ctx context.Context, discountCodeID string, opts ...CallOption) (rsp *BaseResponse, err error) {
 	if discountCodeID == "" {
 		return nil, errors.New("discount code id is required")
 	}
 
 	path := fmt.Sprintf(discountCodeDelete, discountCodeID)
-	res, bs, err := c.doCreemDelete(ctx, path)
+	meta, bs, err := c.doCreemDelete(ctx, apiOp{Name: "DeleteDiscountCode", ResourceID: discountCodeID}, path, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	rsp = &BaseResponse{Code: gocreem.Success}
-	if res.StatusCode != http.StatusNoContent {
-		rsp.Code = res.StatusCode
+	rsp = &BaseResponse{Code: gocreem.Success, Meta: meta}
+	if meta.StatusCode != http.StatusNoContent {
+		rsp.Code = meta.StatusCode
 		rsp.Error = string(bs)
 		rsp.ErrorResponse = new(ErrorResponse)
 		_ = json.Unmarshal(bs, rsp.ErrorResponse)
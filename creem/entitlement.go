@@ -0,0 +1,292 @@
+package creem
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloud-evan/gocreem"
+	"github.com/cloud-evan/gocreem/pkg/jwt"
+)
+
+const (
+	EntitlementSourceLicense      = "license"
+	EntitlementSourceSubscription = "subscription"
+
+	defaultEntitlementTTL = 15 * time.Minute
+)
+
+// EntitlementClaims 授权令牌声明，Subject 为客户ID
+type EntitlementClaims struct {
+	jwt.RegisteredClaims
+	CustomerID     string           `json:"customer_id"`
+	ProductID      string           `json:"product_id"`
+	Status         string           `json:"status"`
+	Source         string           `json:"source"`                    // license / subscription
+	SubscriptionID string           `json:"subscription_id,omitempty"` // 来源为订阅时
+	EntitledUntil  *jwt.NumericDate `json:"entitled_until,omitempty"`  // 授权或当前计费周期的结束时间，nil 表示永久
+}
+
+type entitlementConfig struct {
+	issuer   string
+	audience string
+	ttl      time.Duration
+	leeway   time.Duration
+	keyfunc  jwt.Keyfunc
+	now      func() time.Time
+}
+
+type EntitlementOption func(*entitlementConfig)
+
+// WithEntitlementIssuer 设置 iss，校验端会要求一致
+func WithEntitlementIssuer(issuer string) EntitlementOption {
+	return func(c *entitlementConfig) {
+		c.issuer = issuer
+	}
+}
+
+// WithEntitlementAudience 设置 aud，校验端会要求一致
+func WithEntitlementAudience(audience string) EntitlementOption {
+	return func(c *entitlementConfig) {
+		c.audience = audience
+	}
+}
+
+// WithEntitlementTTL 设置令牌有效期，默认15分钟
+func WithEntitlementTTL(ttl time.Duration) EntitlementOption {
+	return func(c *entitlementConfig) {
+		if ttl > 0 {
+			c.ttl = ttl
+		}
+	}
+}
+
+// WithEntitlementLeeway 校验端允许的时钟偏差
+func WithEntitlementLeeway(leeway time.Duration) EntitlementOption {
+	return func(c *entitlementConfig) {
+		c.leeway = leeway
+	}
+}
+
+// WithEntitlementKeyfunc 校验端使用自定义公钥查找，如 jwt.NewJWKSCache(url).Keyfunc，
+// 设置后 AddKey 添加的公钥不再生效
+func WithEntitlementKeyfunc(keyfunc jwt.Keyfunc) EntitlementOption {
+	return func(c *entitlementConfig) {
+		c.keyfunc = keyfunc
+	}
+}
+
+func newEntitlementConfig(options []EntitlementOption) entitlementConfig {
+	cfg := entitlementConfig{ttl: defaultEntitlementTTL, now: time.Now}
+	for _, option := range options {
+		option(&cfg)
+	}
+	return cfg
+}
+
+// EntitlementIssuer 在 ValidateLicense / GetSubscription 成功后签发短期授权令牌，
+// 下游服务用 EntitlementVerifier 在本地校验，无需每次请求 Creem
+type EntitlementIssuer struct {
+	cfg    entitlementConfig
+	method jwt.SigningMethod
+
+	mu        sync.RWMutex
+	keys      map[string]any
+	activeKid string
+}
+
+// NewEntitlementIssuer 初始化签发器
+// method: 签名算法，如 jwt.SigningMethodES256
+// kid: 当前签名密钥ID，写入令牌头部
+// key: 签名私钥，类型需与 method 匹配
+func NewEntitlementIssuer(method jwt.SigningMethod, kid string, key any, options ...EntitlementOption) (*EntitlementIssuer, error) {
+	if method == nil || kid == "" || key == nil {
+		return nil, gocreem.MissParamErr
+	}
+	return &EntitlementIssuer{
+		cfg:       newEntitlementConfig(options),
+		method:    method,
+		keys:      map[string]any{kid: key},
+		activeKid: kid,
+	}, nil
+}
+
+// Rotate 切换到新的签名密钥，旧密钥保留直到 RemoveKey
+func (i *EntitlementIssuer) Rotate(kid string, key any) error {
+	if kid == "" || key == nil {
+		return gocreem.MissParamErr
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.keys[kid] = key
+	i.activeKid = kid
+	return nil
+}
+
+// RemoveKey 删除不再使用的签名密钥，不能删除当前密钥
+func (i *EntitlementIssuer) RemoveKey(kid string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if kid != i.activeKid {
+		delete(i.keys, kid)
+	}
+}
+
+// ActiveKeyID 当前签名密钥ID
+func (i *EntitlementIssuer) ActiveKeyID() string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.activeKid
+}
+
+// Issue 签发令牌，补全 iss/aud/iat/exp，exp 不晚于 EntitledUntil
+func (i *EntitlementIssuer) Issue(claims *EntitlementClaims) (string, error) {
+	if claims == nil {
+		return "", gocreem.MissParamErr
+	}
+	i.mu.RLock()
+	kid, key := i.activeKid, i.keys[i.activeKid]
+	i.mu.RUnlock()
+
+	now := i.cfg.now()
+	exp := now.Add(i.cfg.ttl)
+	if claims.EntitledUntil != nil && claims.EntitledUntil.Before(exp) {
+		exp = claims.EntitledUntil.Time
+	}
+	if !exp.After(now) {
+		return "", EntitlementNotActiveErr
+	}
+
+	c := *claims
+	c.Subject = c.CustomerID
+	c.Issuer = i.cfg.issuer
+	c.Audience = nil
+	if i.cfg.audience != "" {
+		c.Audience = jwt.ClaimStrings{i.cfg.audience}
+	}
+	c.IssuedAt = jwt.NewNumericDate(now)
+	c.ExpiresAt = jwt.NewNumericDate(exp)
+
+	token := jwt.NewWithClaims(i.method, &c)
+	token.Header["kid"] = kid
+	return token.SignedString(key)
+}
+
+// IssueFromLicense 授权密钥校验通过后签发令牌
+func (i *EntitlementIssuer) IssueFromLicense(rsp *LicenseValidateResponse) (string, error) {
+	if rsp == nil {
+		return "", gocreem.MissParamErr
+	}
+	if rsp.Code != gocreem.Success || !rsp.Data.Valid {
+		return "", EntitlementNotActiveErr
+	}
+	claims := &EntitlementClaims{
+		CustomerID: rsp.Data.Customer,
+		ProductID:  rsp.Data.ProductID,
+		Status:     StatusActive,
+		Source:     EntitlementSourceLicense,
+	}
+	if rsp.Data.ExpiresAt != nil {
+		claims.EntitledUntil = jwt.NewNumericDate(*rsp.Data.ExpiresAt)
+	}
+	return i.Issue(claims)
+}
+
+// IssueFromSubscription 订阅处于有效状态时签发令牌，有效期不超过当前计费周期
+func (i *EntitlementIssuer) IssueFromSubscription(sub *Subscription) (string, error) {
+	if sub == nil {
+		return "", gocreem.MissParamErr
+	}
+	if !sub.Status.IsEntitled() {
+		return "", fmt.Errorf("[%w]: subscription status %s", EntitlementNotActiveErr, sub.Status)
+	}
+	claims := &EntitlementClaims{
+		CustomerID:     sub.CustomerID,
+		ProductID:      sub.ProductID,
+		Status:         string(sub.Status),
+		Source:         EntitlementSourceSubscription,
+		SubscriptionID: sub.ID,
+	}
+	if !sub.CurrentPeriodEnd.IsZero() {
+		claims.EntitledUntil = jwt.NewNumericDate(sub.CurrentPeriodEnd)
+	}
+	return i.Issue(claims)
+}
+
+// EntitlementVerifier 按 kid 查找公钥校验授权令牌
+type EntitlementVerifier struct {
+	cfg    entitlementConfig
+	parser *jwt.Parser
+
+	mu   sync.RWMutex
+	keys map[string]any
+}
+
+// NewEntitlementVerifier 初始化校验器
+// methods: 允许的签名算法，如 "ES256"，至少指定一个
+func NewEntitlementVerifier(methods []string, options ...EntitlementOption) (*EntitlementVerifier, error) {
+	if len(methods) == 0 {
+		return nil, MissEntitlementMethodsErr
+	}
+	cfg := newEntitlementConfig(options)
+	parserOptions := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(cfg.leeway),
+		jwt.WithTimeFunc(cfg.now),
+	}
+	if cfg.issuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(cfg.issuer))
+	}
+	if cfg.audience != "" {
+		parserOptions = append(parserOptions, jwt.WithAudience(cfg.audience))
+	}
+	return &EntitlementVerifier{
+		cfg:    cfg,
+		parser: jwt.NewParser(parserOptions...),
+		keys:   make(map[string]any),
+	}, nil
+}
+
+// AddKey 添加校验公钥，轮换期间新旧密钥可同时存在
+func (v *EntitlementVerifier) AddKey(kid string, key any) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.keys[kid] = key
+}
+
+// RemoveKey 删除校验公钥
+func (v *EntitlementVerifier) RemoveKey(kid string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	delete(v.keys, kid)
+}
+
+// Keyfunc 供 jwt.Parser 使用的按 kid 取公钥函数
+func (v *EntitlementVerifier) Keyfunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	v.mu.RLock()
+	key, ok := v.keys[kid]
+	v.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("[%w]: kid %q", EntitlementKeyNotFoundErr, kid)
+	}
+	return key, nil
+}
+
+// Verify 校验签名、有效期（允许 leeway 偏差）、iss 和 aud，返回令牌声明
+func (v *EntitlementVerifier) Verify(tokenString string) (*EntitlementClaims, error) {
+	keyfunc := v.Keyfunc
+	if v.cfg.keyfunc != nil {
+		keyfunc = v.cfg.keyfunc
+	}
+	token, err := jwt.ParseTypedWithParser[*EntitlementClaims](v.parser, tokenString, keyfunc)
+	if err != nil {
+		return nil, err
+	}
+	if token.Claims.CustomerID == "" {
+		return nil, errors.New("entitlement token missing customer_id")
+	}
+	return token.Claims, nil
+}
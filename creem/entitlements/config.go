@@ -0,0 +1,86 @@
+package entitlements
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config 产品与功能的映射，YAML 或 JSON：
+//
+//	products:
+//	  prod_basic: [export]
+//	  prod_pro: [export, api, sso]
+type Config struct {
+	Products map[string][]string `json:"products" yaml:"products"` // product_id -> 功能列表
+}
+
+// LoadConfig 读取配置文件，按扩展名 .yaml/.yml/.json 解析
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return nil, fmt.Errorf("entitlements: unsupported config file %s", path)
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig 解析 YAML 或 JSON 格式的配置
+func ParseConfig(data []byte) (*Config, error) {
+	cfg := new(Config)
+	// JSON 是 YAML 的子集，统一按 YAML 解析
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("entitlements: parse config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate 校验产品ID和功能名不为空
+func (c *Config) Validate() error {
+	if len(c.Products) == 0 {
+		return fmt.Errorf("entitlements: no products configured")
+	}
+	for productID, features := range c.Products {
+		if productID == "" {
+			return fmt.Errorf("entitlements: empty product id")
+		}
+		for _, feature := range features {
+			if feature == "" {
+				return fmt.Errorf("entitlements: empty feature for product %s", productID)
+			}
+		}
+	}
+	return nil
+}
+
+// Features 产品包含的功能
+func (c *Config) Features(productID string) []string {
+	return c.Products[productID]
+}
+
+// AllFeatures 配置中出现过的全部功能，已排序去重
+func (c *Config) AllFeatures() []string {
+	set := make(map[string]struct{})
+	for _, features := range c.Products {
+		for _, feature := range features {
+			set[feature] = struct{}{}
+		}
+	}
+	list := make([]string, 0, len(set))
+	for feature := range set {
+		list = append(list, feature)
+	}
+	sort.Strings(list)
+	return list
+}
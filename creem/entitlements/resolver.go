@@ -0,0 +1,325 @@
+// Package entitlements 根据产品与功能的映射解析客户可使用的功能，
+// 结果按客户缓存，通过 Subscribe 接入 EventBus 后收到订阅、退款等 Webhook 事件时自动失效
+//
+//	cfg, err := entitlements.LoadConfig("entitlements.yaml")
+//	resolver, err := entitlements.NewResolver(client, cfg)
+//	defer resolver.Subscribe(bus)()
+//	ok, err := resolver.Allowed(ctx, customerID, "sso")
+package entitlements
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloud-evan/gocreem"
+	"github.com/cloud-evan/gocreem/creem"
+)
+
+const (
+	defaultTTL            = 5 * time.Minute
+	subscriptionsPageSize = 100
+
+	SourceSubscription = "subscription"
+	SourceLicense      = "license"
+)
+
+// Grant 一项功能的来源
+type Grant struct {
+	Feature        string
+	ProductID      string
+	Source         string     // SourceSubscription 或 SourceLicense
+	SubscriptionID string     // Source 为订阅时
+	ExpiresAt      *time.Time // 为空表示不过期，订阅为当前计费周期结束时间
+}
+
+// Entitlements 客户当前可使用的功能
+type Entitlements struct {
+	CustomerID string
+	Grants     []Grant
+	ResolvedAt time.Time
+}
+
+// Has 是否拥有未过期的功能
+func (e *Entitlements) Has(feature string) bool {
+	return e.has(feature, time.Now())
+}
+
+func (e *Entitlements) has(feature string, now time.Time) bool {
+	for _, grant := range e.Grants {
+		if grant.Feature == feature && (grant.ExpiresAt == nil || grant.ExpiresAt.After(now)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Features 拥有的功能，已排序去重
+func (e *Entitlements) Features() []string {
+	set := make(map[string]struct{}, len(e.Grants))
+	for _, grant := range e.Grants {
+		set[grant.Feature] = struct{}{}
+	}
+	list := make([]string, 0, len(set))
+	for feature := range set {
+		list = append(list, feature)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// LicenseKeysFunc 返回客户持有的授权密钥，Creem 暂不支持按客户查询授权
+type LicenseKeysFunc func(ctx context.Context, customerID string) ([]string, error)
+
+type cacheEntry struct {
+	ent       *Entitlements
+	expiresAt time.Time
+}
+
+// inflight 客户正在进行的解析，解析期间该客户被失效时 epoch 递增，结果不写入缓存
+type inflight struct {
+	n     int
+	epoch uint64
+}
+
+// Resolver 解析并缓存客户的功能
+type Resolver struct {
+	client      *creem.Client
+	config      *Config
+	ttl         time.Duration
+	licenseKeys LicenseKeysFunc
+	now         func() time.Time
+
+	mu       sync.Mutex
+	cache    map[string]*cacheEntry
+	inflight map[string]*inflight
+	epoch    uint64 // InvalidateAll 时递增，解析期间发生时不写入缓存
+}
+
+type Option func(*Resolver)
+
+// WithTTL 设置缓存时间，默认5分钟；订阅周期先于 TTL 结束时以周期结束时间为准
+func WithTTL(ttl time.Duration) Option {
+	return func(r *Resolver) {
+		if ttl > 0 {
+			r.ttl = ttl
+		}
+	}
+}
+
+// WithLicenseKeys 设置客户授权密钥来源，未设置时只解析订阅
+func WithLicenseKeys(fn LicenseKeysFunc) Option {
+	return func(r *Resolver) {
+		r.licenseKeys = fn
+	}
+}
+
+// NewResolver 初始化解析器
+func NewResolver(client *creem.Client, config *Config, options ...Option) (*Resolver, error) {
+	if client == nil {
+		return nil, creem.MissCreemInitParamErr
+	}
+	if config == nil {
+		return nil, gocreem.MissParamErr
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	r := &Resolver{
+		client:   client,
+		config:   config,
+		ttl:      defaultTTL,
+		now:      time.Now,
+		cache:    make(map[string]*cacheEntry),
+		inflight: make(map[string]*inflight),
+	}
+	for _, option := range options {
+		option(r)
+	}
+	return r, nil
+}
+
+// Allowed 客户是否可以使用功能
+func (r *Resolver) Allowed(ctx context.Context, customerID, feature string) (bool, error) {
+	ent, err := r.Resolve(ctx, customerID)
+	if err != nil {
+		return false, err
+	}
+	return ent.has(feature, r.now()), nil
+}
+
+// Resolve 解析客户的功能，命中缓存时不发起请求
+func (r *Resolver) Resolve(ctx context.Context, customerID string) (*Entitlements, error) {
+	if customerID == "" {
+		return nil, creem.MissCustomerIdErr
+	}
+
+	now := r.now()
+	r.mu.Lock()
+	entry, ok := r.cache[customerID]
+	if ok && now.Before(entry.expiresAt) {
+		r.mu.Unlock()
+		return entry.ent, nil
+	}
+	fill := r.inflight[customerID]
+	if fill == nil {
+		fill = new(inflight)
+		r.inflight[customerID] = fill
+	}
+	fill.n++
+	epoch, customerEpoch := r.epoch, fill.epoch
+	r.mu.Unlock()
+
+	ent, err := r.resolve(ctx, customerID, now)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if fill.n--; fill.n == 0 {
+		delete(r.inflight, customerID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := now.Add(r.ttl)
+	for _, grant := range ent.Grants {
+		if grant.ExpiresAt != nil && grant.ExpiresAt.Before(expiresAt) {
+			expiresAt = *grant.ExpiresAt
+		}
+	}
+	// 解析期间该客户或全部缓存被失效时，结果可能已过时，不写入缓存
+	if r.epoch == epoch && fill.epoch == customerEpoch {
+		r.cache[customerID] = &cacheEntry{ent: ent, expiresAt: expiresAt}
+	}
+	return ent, nil
+}
+
+// Invalidate 清除客户的缓存，不影响其他客户正在进行的解析
+func (r *Resolver) Invalidate(customerID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.cache, customerID)
+	if fill := r.inflight[customerID]; fill != nil {
+		fill.epoch++
+	}
+}
+
+// InvalidateAll 清除全部缓存
+func (r *Resolver) InvalidateAll() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cache = make(map[string]*cacheEntry)
+	r.epoch++
+}
+
+// Subscribe 在 EventBus 上注册 HandleEvent，Webhook 处理器 Publish 事件后自动失效缓存，返回的 cancel 用于取消注册
+func (r *Resolver) Subscribe(bus *creem.EventBus) (cancel func()) {
+	return bus.SubscribeFunc(r.HandleEvent)
+}
+
+// HandleEvent 根据 Webhook 事件清除相关客户的缓存，未使用 Subscribe 时在 ParseWebhook 之后调用。
+// 事件对象中没有客户ID时（如部分退款、争议事件）清除全部缓存
+func (r *Resolver) HandleEvent(event *creem.WebhookEvent) {
+	if event == nil || !invalidates(event.EventType) {
+		return
+	}
+	var object struct {
+		CustomerID string `json:"customer_id"`
+	}
+	if err := json.Unmarshal(event.Object, &object); err != nil || object.CustomerID == "" {
+		r.InvalidateAll()
+		return
+	}
+	r.Invalidate(object.CustomerID)
+}
+
+// invalidates 会改变客户功能的事件
+func invalidates(eventType string) bool {
+	switch eventType {
+	case creem.EventCheckoutCompleted, creem.EventRefundCreated, creem.EventDisputeCreated:
+		return true
+	}
+	return strings.HasPrefix(eventType, "subscription.")
+}
+
+func (r *Resolver) resolve(ctx context.Context, customerID string, now time.Time) (*Entitlements, error) {
+	ent := &Entitlements{CustomerID: customerID, ResolvedAt: now}
+
+	params := &creem.ListParams{CustomerID: customerID}
+	params.Limit = subscriptionsPageSize
+	for params.Page = 1; ; params.Page++ {
+		rsp, err := r.client.ListSubscriptions(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		if rsp.Code != gocreem.Success {
+			return nil, fmt.Errorf("entitlements: list subscriptions status %d: %s", rsp.Code, rsp.Error)
+		}
+		for i := range rsp.Data {
+			sub := &rsp.Data[i]
+			if !sub.Status.IsEntitled() {
+				continue
+			}
+			var expiresAt *time.Time
+			if !sub.CurrentPeriodEnd.IsZero() {
+				end := sub.CurrentPeriodEnd
+				expiresAt = &end
+			}
+			for _, feature := range r.config.Features(sub.ProductID) {
+				ent.Grants = append(ent.Grants, Grant{
+					Feature:        feature,
+					ProductID:      sub.ProductID,
+					Source:         SourceSubscription,
+					SubscriptionID: sub.ID,
+					ExpiresAt:      expiresAt,
+				})
+			}
+		}
+		// 未返回 total_count 时以不满一页为结束
+		if len(rsp.Data) < params.Limit || (rsp.TotalCount > 0 && params.Page*params.Limit >= rsp.TotalCount) {
+			break
+		}
+	}
+
+	if r.licenseKeys == nil {
+		return ent, nil
+	}
+	keys, err := r.licenseKeys(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		rsp, err := r.client.ValidateLicense(ctx, &creem.LicenseValidateRequest{LicenseKey: key})
+		if err != nil {
+			return nil, err
+		}
+		// 4xx 表示授权无效，跳过；限流和服务端错误不缓存
+		if rsp.Code == http.StatusTooManyRequests || rsp.Code >= http.StatusInternalServerError {
+			return nil, fmt.Errorf("entitlements: validate license status %d: %s", rsp.Code, rsp.Error)
+		}
+		if rsp.Code != gocreem.Success || !rsp.Data.Valid {
+			continue
+		}
+		if rsp.Data.Customer != "" && rsp.Data.Customer != customerID {
+			continue
+		}
+		if rsp.Data.ExpiresAt != nil && !rsp.Data.ExpiresAt.After(now) {
+			continue
+		}
+		for _, feature := range r.config.Features(rsp.Data.ProductID) {
+			ent.Grants = append(ent.Grants, Grant{
+				Feature:   feature,
+				ProductID: rsp.Data.ProductID,
+				Source:    SourceLicense,
+				ExpiresAt: rsp.Data.ExpiresAt,
+			})
+		}
+	}
+	return ent, nil
+}
@@ -0,0 +1,114 @@
+package entitlements
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cloud-evan/gocreem/creem"
+)
+
+// testServer 返回每个客户一个 active 订阅，onList 在响应前调用
+func testServer(t *testing.T, onList func(customerID string)) (*creem.Client, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		customerID := r.URL.Query().Get("customer_id")
+		if onList != nil {
+			onList(customerID)
+		}
+		fmt.Fprintf(w, `{"data":[{"id":"sub_%s","customer_id":%q,"product_id":"prod_pro","status":"active"}],"total_count":1}`, customerID, customerID)
+	}))
+	t.Cleanup(srv.Close)
+	client, err := creem.NewClient("key", "secret", true, creem.WithProxyUrl(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, &calls
+}
+
+func newTestResolver(t *testing.T, client *creem.Client) *Resolver {
+	t.Helper()
+	r, err := NewResolver(client, &Config{Products: map[string][]string{"prod_pro": {"sso"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func subscriptionEvent(t *testing.T, customerID string) *creem.WebhookEvent {
+	t.Helper()
+	object, err := json.Marshal(map[string]string{"customer_id": customerID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &creem.WebhookEvent{EventType: creem.EventSubscriptionCanceled, Object: object}
+}
+
+func TestResolverInvalidateOtherCustomerKeepsFill(t *testing.T) {
+	var r *Resolver
+	client, calls := testServer(t, func(customerID string) {
+		if customerID == "cus_b" {
+			r.Invalidate("cus_a")
+		}
+	})
+	r = newTestResolver(t, client)
+
+	for i := 0; i < 2; i++ {
+		if ok, err := r.Allowed(context.Background(), "cus_b", "sso"); err != nil || !ok {
+			t.Fatalf("Allowed = %t, %v", ok, err)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("expected cached result for cus_b, got %d requests", n)
+	}
+}
+
+func TestResolverInvalidateDuringResolveSkipsFill(t *testing.T) {
+	var r *Resolver
+	client, calls := testServer(t, func(customerID string) {
+		r.Invalidate(customerID)
+	})
+	r = newTestResolver(t, client)
+
+	for i := 0; i < 2; i++ {
+		if _, err := r.Resolve(context.Background(), "cus_a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := calls.Load(); n != 2 {
+		t.Fatalf("expected stale result not to be cached, got %d requests", n)
+	}
+	if len(r.inflight) != 0 {
+		t.Fatalf("expected no in-flight entries, got %d", len(r.inflight))
+	}
+}
+
+func TestResolverSubscribe(t *testing.T) {
+	client, calls := testServer(t, nil)
+	r := newTestResolver(t, client)
+	bus := creem.NewEventBus()
+	cancel := r.Subscribe(bus)
+
+	ctx := context.Background()
+	_, _ = r.Resolve(ctx, "cus_a")
+	_, _ = r.Resolve(ctx, "cus_b")
+	bus.Publish(subscriptionEvent(t, "cus_a"))
+	_, _ = r.Resolve(ctx, "cus_a")
+	_, _ = r.Resolve(ctx, "cus_b")
+	if n := calls.Load(); n != 3 {
+		t.Fatalf("expected only cus_a to be refetched, got %d requests", n)
+	}
+
+	cancel()
+	bus.Publish(subscriptionEvent(t, "cus_a"))
+	_, _ = r.Resolve(ctx, "cus_a")
+	if n := calls.Load(); n != 3 {
+		t.Fatalf("expected no invalidation after cancel, got %d requests", n)
+	}
+}
@@ -122,4 +122,32 @@ var (
 	MissPlanCreatedAtRequiredErr    = errors.New("plan created at is required")
 	MissPlanUpdatedAtRequiredErr    = errors.New("plan updated at is required")
 	MissPlanDeletedAtRequiredErr    = errors.New("plan deleted at is required")
+	MissLicenseKeyErr               = errors.New("missing license key")
+	MissLicenseCacheKeyErr          = errors.New("missing license cache key")
+	LicenseCacheMissErr             = errors.New("license cache not found")
+	LicenseCacheTamperedErr         = errors.New("license cache signature mismatch")
+	LicenseGraceExpiredErr          = errors.New("license offline grace period expired")
+	EntitlementNotActiveErr         = errors.New("entitlement is not active")
+	EntitlementKeyNotFoundErr       = errors.New("entitlement signing key not found")
+	MissEntitlementMethodsErr       = errors.New("missing entitlement signing methods")
+	StopStreamErr                   = errors.New("stop stream") // 流式回调返回时提前结束，不视为错误
+	MissTenantIdErr                 = errors.New("missing tenant id")
+	MissCredentialProviderErr       = errors.New("missing credential provider")
+	MissCredentialsErr              = errors.New("credential provider returned empty api key or secret key")
+	TenantNotFoundErr               = errors.New("tenant not found")
+	InvalidCancelModeErr            = errors.New("invalid cancel mode")
+	SubscriptionStatusErr           = errors.New("operation not allowed in current subscription status")
+	InvalidProrationBasisErr        = errors.New("invalid proration basis")
+	InvalidBillingPeriodErr         = errors.New("invalid subscription billing period")
+	CurrencyMismatchErr             = errors.New("currency mismatch")
+	UnsupportedSubscriptionItemsErr = errors.New("subscription with multiple items is not supported")
+	InvalidUpdateBehaviorErr        = errors.New("invalid update behavior")
+	InvalidQuantityErr              = errors.New("quantity must be positive")
+	MissSubscriptionItemErr         = errors.New("missing subscription item id or product id")
+	InvalidUrlErr                   = errors.New("url must be absolute http or https")
+	InvalidEmailErr                 = errors.New("invalid email")
+	InvalidMetadataErr              = errors.New("invalid metadata")
+	DuplicatePriceErr               = errors.New("duplicate product price for currency and billing cycle")
+	PriceNotFoundErr                = errors.New("product has no price for currency and billing cycle")
+	UsageQueueFullErr               = errors.New("usage recorder pending queue is full")
 )
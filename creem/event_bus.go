@@ -0,0 +1,83 @@
+package creem
+
+import "sync"
+
+// EventBus 进程内 Webhook 事件分发，Webhook 处理器中 Publish，WaitForCheckout 等订阅。
+// 通道订阅者处理不及时时丢弃事件，不会阻塞 Publish；SubscribeFunc 注册的函数在 Publish 中同步调用，不会丢弃
+type EventBus struct {
+	mu       sync.RWMutex
+	nextID   int
+	subs     map[int]chan *WebhookEvent
+	handlers map[int]func(*WebhookEvent)
+}
+
+// NewEventBus 初始化事件总线
+func NewEventBus() *EventBus {
+	return &EventBus{
+		subs:     make(map[int]chan *WebhookEvent),
+		handlers: make(map[int]func(*WebhookEvent)),
+	}
+}
+
+// Publish 分发事件给所有订阅者，处理函数在通道订阅者之后依次调用
+func (b *EventBus) Publish(event *WebhookEvent) {
+	if event == nil {
+		return
+	}
+	b.mu.RLock()
+	for _, ch := range b.subs {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+	handlers := make([]func(*WebhookEvent), 0, len(b.handlers))
+	for _, fn := range b.handlers {
+		handlers = append(handlers, fn)
+	}
+	b.mu.RUnlock()
+
+	// 在锁外调用，处理函数中可以订阅或取消订阅
+	for _, fn := range handlers {
+		fn(event)
+	}
+}
+
+// SubscribeFunc 注册处理函数，每个事件都会在 Publish 中同步调用，fn 应尽快返回。
+// 适合缓存失效等不能丢事件的场景，返回的 cancel 用于取消注册
+func (b *EventBus) SubscribeFunc(fn func(*WebhookEvent)) (cancel func()) {
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = fn
+	b.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.handlers, id)
+			b.mu.Unlock()
+		})
+	}
+}
+
+// Subscribe 订阅事件，buffer 为通道缓冲大小，返回的 cancel 用于取消订阅并关闭通道
+func (b *EventBus) Subscribe(buffer int) (events <-chan *WebhookEvent, cancel func()) {
+	ch := make(chan *WebhookEvent, max(buffer, 1))
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.subs[id] = ch
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, id)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package creem
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+	"os"
+	"sort"
+	"strings"
+)
+
+// MachineFingerprint 返回当前设备的稳定指纹，可作为 LicenseActivateRequest.InstanceName，
+// 同一台机器重复激活时得到相同的值。
+// Linux 上由 hostname、machine-id 和物理网卡 MAC 派生，其他平台不包含 machine-id。
+func MachineFingerprint() (string, error) {
+	hostname, _ := os.Hostname()
+	machineID := readMachineID()
+	mac := primaryMAC()
+	if hostname == "" && machineID == "" && mac == "" {
+		return "", errors.New("no machine identifiers available")
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join([]string{"creem-fp-v1", hostname, machineID, mac}, "|")))
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+// primaryMAC 返回按名称排序后第一个可用网卡的MAC，跳过回环和虚拟网卡
+func primaryMAC() string {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return ""
+	}
+	sort.Slice(ifaces, func(i, j int) bool { return ifaces[i].Name < ifaces[j].Name })
+
+	var fallback string
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 || len(iface.HardwareAddr) == 0 || isVirtualInterface(iface.Name) {
+			continue
+		}
+		if isPhysicalInterface(iface.Name) {
+			return iface.HardwareAddr.String()
+		}
+		if fallback == "" {
+			fallback = iface.HardwareAddr.String()
+		}
+	}
+	return fallback
+}
+
+func isVirtualInterface(name string) bool {
+	for _, prefix := range []string{"docker", "veth", "br-", "virbr", "vmnet", "vboxnet", "tun", "tap", "utun", "bridge", "cni", "flannel"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+//go:build linux
+// +build linux
+
+package creem
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// readMachineID 读取 systemd/dbus 的 machine-id
+func readMachineID() string {
+	for _, path := range []string{"/etc/machine-id", "/var/lib/dbus/machine-id"} {
+		if bs, err := os.ReadFile(path); err == nil {
+			if id := strings.TrimSpace(string(bs)); id != "" {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+// isPhysicalInterface 物理网卡在 sysfs 中有 device 链接
+func isPhysicalInterface(name string) bool {
+	_, err := os.Stat(filepath.Join("/sys/class/net", name, "device"))
+	return err == nil
+}
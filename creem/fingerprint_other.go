@@ -0,0 +1,12 @@
+//go:build !linux
+// +build !linux
+
+package creem
+
+func readMachineID() string {
+	return ""
+}
+
+func isPhysicalInterface(name string) bool {
+	return false
+}
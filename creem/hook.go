@@ -0,0 +1,41 @@
+package creem
+
+import (
+	"context"
+	"time"
+)
+
+// RequestInfo 单次 API 调用的信息，BeforeRequest 时只有请求字段，AfterRequest 时补全结果
+type RequestInfo struct {
+	Operation  string        // 接口名，如 CreateCheckoutSession
+	Method     string        // HTTP 方法
+	Path       string        // 请求路径，不含 baseUrl
+	ResourceID string        // 资源ID，如结账会话ID、订阅ID，可能为空
+	StatusCode int           // HTTP 状态码，网络错误时为 0
+	Retries    int           // 重试次数
+	Duration   time.Duration // 总耗时
+	Err        error         // 网络错误或读取响应失败
+}
+
+// Hook 请求钩子，用于接入链路追踪、指标等，见 creem/otel
+type Hook interface {
+	// BeforeRequest 发送请求前调用，返回的 ctx 用于本次请求
+	BeforeRequest(ctx context.Context, info *RequestInfo) context.Context
+	// AfterRequest 请求结束后调用，按注册顺序的逆序执行
+	AfterRequest(ctx context.Context, info *RequestInfo)
+}
+
+// apiOp 标识一次 API 调用
+type apiOp struct {
+	Name       string
+	ResourceID string
+}
+
+// WithHook 添加请求钩子，可多次使用
+func WithHook(hook Hook) Option {
+	return func(c *Client) {
+		if hook != nil {
+			c.pending.hooks = append(c.pending.hooks, hook)
+		}
+	}
+}
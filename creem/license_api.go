@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"net/http"
 
 	"github.com/cloud-evan/gocreem"
@@ -12,7 +11,7 @@ import (
 
 // ValidateLicense 校验授权密钥
 // 文档：https://docs.creem.io/api-reference/license#validate-license-key
-func (c *Client) ValidateLicense(ctx context.Context, req *LicenseValidateRequest) (rsp *LicenseValidateResponse, err error) {
+func (c *Client) ValidateLicense(ctx context.Context, req *LicenseValidateRequest, opts ...CallOption) (rsp *LicenseValidateResponse, err error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
@@ -22,21 +21,22 @@ func (c *Client) ValidateLicense(ctx context.Context, req *LicenseValidateReques
 		return nil, errors.New("license key is required")
 	}
 
-	res, bs, err := c.doCreemPost(ctx, req, licenseValidate)
+	meta, bs, err := c.doCreemPost(ctx, apiOp{Name: "ValidateLicense"}, req, licenseValidate, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	rsp = &LicenseValidateResponse{BaseResponse: BaseResponse{Code: gocreem.Success}}
-	if err = json.Unmarshal(bs, rsp); err != nil {
-		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gocreem.UnmarshalErr, err, string(bs))
-	}
-
-	if res.StatusCode != http.StatusOK {
-		rsp.Code = res.StatusCode
+	rsp = &LicenseValidateResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusOK {
+		rsp.Code = meta.StatusCode
 		rsp.Error = string(bs)
 		rsp.ErrorResponse = new(ErrorResponse)
 		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, unmarshalResponseErr(meta, bs, err)
 	}
 
 	return rsp, nil
@@ -44,7 +44,7 @@ func (c *Client) ValidateLicense(ctx context.Context, req *LicenseValidateReques
 
 // ActivateLicense 激活授权密钥
 // 文档：https://docs.creem.io/api-reference/license#activate-license-key
-func (c *Client) ActivateLicense(ctx context.Context, req *LicenseActivateRequest) (rsp *LicenseActivateResponse, err error) {
+func (c *Client) ActivateLicense(ctx context.Context, req *LicenseActivateRequest, opts ...CallOption) (rsp *LicenseActivateResponse, err error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
@@ -57,21 +57,22 @@ func (c *Client) ActivateLicense(ctx context.Context, req *LicenseActivateReques
 		return nil, MissCustomerIdErr
 	}
 
-	res, bs, err := c.doCreemPost(ctx, req, licenseActivate)
+	meta, bs, err := c.doCreemPost(ctx, apiOp{Name: "ActivateLicense"}, req, licenseActivate, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	rsp = &LicenseActivateResponse{BaseResponse: BaseResponse{Code: gocreem.Success}}
-	if err = json.Unmarshal(bs, rsp); err != nil {
-		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gocreem.UnmarshalErr, err, string(bs))
-	}
-
-	if res.StatusCode != http.StatusOK {
-		rsp.Code = res.StatusCode
+	rsp = &LicenseActivateResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusOK {
+		rsp.Code = meta.StatusCode
 		rsp.Error = string(bs)
 		rsp.ErrorResponse = new(ErrorResponse)
 		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, unmarshalResponseErr(meta, bs, err)
 	}
 
 	return rsp, nil
@@ -79,7 +80,7 @@ func (c *Client) ActivateLicense(ctx context.Context, req *LicenseActivateReques
 
 // DeactivateLicense 注销授权密钥
 // 文档：https://docs.creem.io/api-reference/license#deactivate-license-key
-func (c *Client) DeactivateLicense(ctx context.Context, req *LicenseDeactivateRequest) (rsp *LicenseDeactivateResponse, err error) {
+func (c *Client) DeactivateLicense(ctx context.Context, req *LicenseDeactivateRequest, opts ...CallOption) (rsp *LicenseDeactivateResponse, err error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
@@ -89,21 +90,22 @@ func (c *Client) DeactivateLicense(ctx context.Context, req *LicenseDeactivateRe
 		return nil, errors.New("license key is required")
 	}
 
-	res, bs, err := c.doCreemPost(ctx, req, licenseDeactivate)
+	meta, bs, err := c.doCreemPost(ctx, apiOp{Name: "DeactivateLicense"}, req, licenseDeactivate, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	rsp = &LicenseDeactivateResponse{BaseResponse: BaseResponse{Code: gocreem.Success}}
-	if err = json.Unmarshal(bs, rsp); err != nil {
-		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gocreem.UnmarshalErr, err, string(bs))
-	}
-
-	if res.StatusCode != http.StatusOK {
-		rsp.Code = res.StatusCode
+	rsp = &LicenseDeactivateResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusOK {
+		rsp.Code = meta.StatusCode
 		rsp.Error = string(bs)
 		rsp.ErrorResponse = new(ErrorResponse)
 		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, unmarshalResponseErr(meta, bs, err)
 	}
 
 	return rsp, nil
@@ -0,0 +1,461 @@
+package creem
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloud-evan/gocreem"
+)
+
+const (
+	defaultLicenseGracePeriod      = 7 * 24 * time.Hour
+	defaultLicenseRevalidatePeriod = 6 * time.Hour
+)
+
+// LicenseState 最近一次确定的授权校验结果，持久化在 LicenseStore 中
+type LicenseState struct {
+	LicenseHash string    `json:"license_hash"` // 授权密钥的SHA-256，不落盘明文
+	Valid       bool      `json:"valid"`
+	Message     string    `json:"message,omitempty"`
+	Customer    string    `json:"customer,omitempty"`
+	Activated   bool      `json:"activated,omitempty"`
+	ValidatedAt time.Time `json:"validated_at"`
+
+	InstanceID      string `json:"instance_id,omitempty"`
+	InstanceName    string `json:"instance_name,omitempty"`
+	ActivationLimit int    `json:"activation_limit,omitempty"`
+	Activation      int    `json:"activation,omitempty"`
+}
+
+// LicenseStatus 授权状态
+type LicenseStatus struct {
+	Valid       bool
+	Offline     bool // true 表示结果来自离线缓存
+	Message     string
+	Customer    string
+	Activated   bool
+	ValidatedAt time.Time
+	GraceUntil  time.Time // 离线可用截止时间
+
+	InstanceID      string
+	ActivationLimit int
+	Activation      int
+
+	StoreErr error // 结果写入 LicenseStore 失败，本次结果仍然有效，但重启后不会保留
+}
+
+// licenseCacheFile 缓存文件格式，Signature 为 Payload 的 HMAC-SHA256
+type licenseCacheFile struct {
+	Payload   []byte `json:"payload"`
+	Signature string `json:"signature"`
+}
+
+// LicenseManager 包装 ValidateLicense / ActivateLicense，
+// 持久化最近一次成功的结果，在离线或 Creem 不可用时于宽限期内继续放行
+type LicenseManager struct {
+	client       *Client
+	licenseKey   string
+	instanceName string
+	store        LicenseStore
+	cacheKey     []byte
+	gracePeriod  time.Duration
+	interval     time.Duration
+	onChange     func(*LicenseStatus)
+	now          func() time.Time
+
+	mu    sync.RWMutex
+	state *LicenseState
+
+	loopMu sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+type LicenseManagerOption func(*LicenseManager)
+
+// NewLicenseManager 初始化授权管理器，必须通过 WithLicenseCacheKey 设置缓存签名密钥
+// 默认保存到用户配置目录下的 creem 目录、7天宽限期、每6小时后台重新校验，实例名默认为 MachineFingerprint()
+func NewLicenseManager(client *Client, licenseKey string, options ...LicenseManagerOption) (*LicenseManager, error) {
+	if client == nil {
+		return nil, MissCreemInitParamErr
+	}
+	if licenseKey == "" {
+		return nil, MissLicenseKeyErr
+	}
+
+	m := &LicenseManager{
+		client:      client,
+		licenseKey:  licenseKey,
+		gracePeriod: defaultLicenseGracePeriod,
+		interval:    defaultLicenseRevalidatePeriod,
+		now:         time.Now,
+	}
+	for _, option := range options {
+		option(m)
+	}
+	if len(m.cacheKey) == 0 {
+		return nil, MissLicenseCacheKeyErr
+	}
+	if m.store == nil {
+		// 实例ID和宽限期需要跨进程保留，默认使用文件存储
+		store, err := NewFileLicenseStore("")
+		if err != nil {
+			return nil, err
+		}
+		m.store = store
+	}
+	if m.instanceName == "" {
+		m.instanceName, _ = MachineFingerprint()
+	}
+	return m, nil
+}
+
+// WithLicenseStore 设置缓存存储，MemoryLicenseStore 在重启后丢失实例ID，每次启动都会重新激活占用席位
+func WithLicenseStore(store LicenseStore) LicenseManagerOption {
+	return func(m *LicenseManager) {
+		if store != nil {
+			m.store = store
+		}
+	}
+}
+
+// WithInstanceName 设置激活时使用的实例名
+func WithInstanceName(name string) LicenseManagerOption {
+	return func(m *LicenseManager) {
+		m.instanceName = name
+	}
+}
+
+// WithLicenseCacheKey 设置缓存签名密钥，必填。
+// 桌面端、命令行等分发给用户的程序使用应用自带的随机密钥即可，切勿使用或内置 Creem 的 API 密钥
+func WithLicenseCacheKey(key []byte) LicenseManagerOption {
+	return func(m *LicenseManager) {
+		m.cacheKey = key
+	}
+}
+
+// WithGracePeriod 设置离线宽限期，从最近一次在线校验成功开始计算
+func WithGracePeriod(d time.Duration) LicenseManagerOption {
+	return func(m *LicenseManager) {
+		if d >= 0 {
+			m.gracePeriod = d
+		}
+	}
+}
+
+// WithRevalidateInterval 设置后台重新校验间隔
+func WithRevalidateInterval(d time.Duration) LicenseManagerOption {
+	return func(m *LicenseManager) {
+		if d > 0 {
+			m.interval = d
+		}
+	}
+}
+
+// WithLicenseChangeHandler 设置后台校验后的回调
+func WithLicenseChangeHandler(fn func(*LicenseStatus)) LicenseManagerOption {
+	return func(m *LicenseManager) {
+		m.onChange = fn
+	}
+}
+
+// Validate 在线校验授权，只有 200 或明确的授权无效/过期响应会更新缓存，
+// 网络错误、服务端错误以及 401/403/404 等密钥或配置错误时回退到缓存
+func (m *LicenseManager) Validate(ctx context.Context) (*LicenseStatus, error) {
+	prev := m.currentState()
+	req := &LicenseValidateRequest{LicenseKey: m.licenseKey}
+	if prev != nil {
+		req.InstanceID = prev.InstanceID
+	}
+
+	rsp, err := m.client.ValidateLicense(ctx, req)
+	if err == nil && isAuthoritativeLicenseResponse(rsp) {
+		state := &LicenseState{
+			LicenseHash:     m.licenseHash(),
+			Valid:           rsp.Code == gocreem.Success && rsp.Data.Valid,
+			Message:         rsp.Data.Message,
+			Customer:        rsp.Data.Customer,
+			ValidatedAt:     m.now(),
+			ActivationLimit: rsp.Data.ActivationLimit,
+			Activation:      rsp.Data.Activation,
+		}
+		if rsp.Code != gocreem.Success && rsp.ErrorResponse != nil {
+			state.Message = rsp.ErrorResponse.Message
+		}
+		if prev != nil && state.Valid {
+			state.Activated = prev.Activated
+			state.InstanceID = prev.InstanceID
+			state.InstanceName = prev.InstanceName
+		}
+		return m.saveStatus(state), nil
+	}
+
+	if err == nil {
+		err = fmt.Errorf("license validate: status %d", rsp.Code)
+	}
+	return m.offline(err)
+}
+
+// Activate 以当前实例名激活授权并刷新缓存。
+// 若本机此前已激活过，会携带缓存的实例ID重新激活，不占用新的席位
+func (m *LicenseManager) Activate(ctx context.Context, customerID string) (*LicenseStatus, error) {
+	req := &LicenseActivateRequest{LicenseKey: m.licenseKey, CustomerID: customerID, InstanceName: m.instanceName}
+	prev := m.currentState()
+	if prev != nil && prev.InstanceName == m.instanceName {
+		req.InstanceID = prev.InstanceID
+	}
+
+	rsp, err := m.client.ActivateLicense(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if rsp.Code != gocreem.Success || !rsp.Data.Success {
+		return nil, fmt.Errorf("license activate: status %d: %s", rsp.Code, rsp.Error)
+	}
+
+	state := &LicenseState{
+		LicenseHash:     m.licenseHash(),
+		Valid:           true,
+		Message:         rsp.Data.Message,
+		Activated:       true,
+		ValidatedAt:     m.now(),
+		InstanceID:      req.InstanceID,
+		InstanceName:    m.instanceName,
+		ActivationLimit: rsp.Data.ActivationLimit,
+		Activation:      rsp.Data.Activation,
+	}
+	if prev != nil {
+		state.Customer = prev.Customer
+	}
+	if rsp.Data.Instance != nil {
+		state.InstanceID = rsp.Data.Instance.ID
+	}
+	return m.saveStatus(state), nil
+}
+
+// Deactivate 注销当前实例，释放席位
+func (m *LicenseManager) Deactivate(ctx context.Context) (*LicenseStatus, error) {
+	prev := m.currentState()
+	req := &LicenseDeactivateRequest{LicenseKey: m.licenseKey}
+	if prev != nil {
+		req.InstanceID = prev.InstanceID
+	}
+
+	rsp, err := m.client.DeactivateLicense(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if rsp.Code != gocreem.Success || !rsp.Data.Success {
+		return nil, fmt.Errorf("license deactivate: status %d: %s", rsp.Code, rsp.Error)
+	}
+
+	state := &LicenseState{
+		LicenseHash:     m.licenseHash(),
+		Message:         rsp.Data.Message,
+		ValidatedAt:     m.now(),
+		ActivationLimit: rsp.Data.ActivationLimit,
+		Activation:      rsp.Data.Activation,
+	}
+	if prev != nil {
+		state.Valid = prev.Valid
+		state.Customer = prev.Customer
+	}
+	return m.saveStatus(state), nil
+}
+
+// Status 返回缓存中的授权状态，不发起网络请求
+func (m *LicenseManager) Status() (*LicenseStatus, error) {
+	return m.offline(nil)
+}
+
+// Start 启动后台定时校验，重复调用无效
+func (m *LicenseManager) Start(ctx context.Context) {
+	m.loopMu.Lock()
+	defer m.loopMu.Unlock()
+	if m.cancel != nil {
+		return
+	}
+	ctx, m.cancel = context.WithCancel(ctx)
+	m.done = make(chan struct{})
+	go m.loop(ctx, m.done)
+}
+
+// Stop 停止后台校验并等待其退出
+func (m *LicenseManager) Stop() {
+	m.loopMu.Lock()
+	cancel, done := m.cancel, m.done
+	m.cancel, m.done = nil, nil
+	m.loopMu.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+func (m *LicenseManager) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			status, err := m.Validate(ctx)
+			if err != nil {
+				status = &LicenseStatus{Offline: true, Message: err.Error()}
+			}
+			if m.onChange != nil {
+				m.onChange(status)
+			}
+		}
+	}
+}
+
+// offline 使用缓存结果，超出宽限期返回 LicenseGraceExpiredErr
+func (m *LicenseManager) offline(cause error) (*LicenseStatus, error) {
+	state := m.cachedState()
+	if state == nil {
+		var err error
+		if state, err = m.load(); err != nil {
+			if cause != nil && errors.Is(err, LicenseCacheMissErr) {
+				return nil, fmt.Errorf("%w (%v)", err, cause)
+			}
+			return nil, err
+		}
+	}
+
+	status := m.status(state, cause != nil)
+	if status.Valid && m.now().After(status.GraceUntil) {
+		if cause != nil {
+			return status, fmt.Errorf("%w (%v)", LicenseGraceExpiredErr, cause)
+		}
+		return status, LicenseGraceExpiredErr
+	}
+	return status, nil
+}
+
+func (m *LicenseManager) status(state *LicenseState, offline bool) *LicenseStatus {
+	return &LicenseStatus{
+		Valid:       state.Valid,
+		Offline:     offline,
+		Message:     state.Message,
+		Customer:    state.Customer,
+		Activated:   state.Activated,
+		ValidatedAt: state.ValidatedAt,
+		GraceUntil:  state.ValidatedAt.Add(m.gracePeriod),
+
+		InstanceID:      state.InstanceID,
+		ActivationLimit: state.ActivationLimit,
+		Activation:      state.Activation,
+	}
+}
+
+func (m *LicenseManager) cachedState() *LicenseState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.state
+}
+
+// currentState 返回内存或存储中的缓存状态，读取失败时返回 nil
+func (m *LicenseManager) currentState() *LicenseState {
+	if state := m.cachedState(); state != nil {
+		return state
+	}
+	state, _ := m.load()
+	return state
+}
+
+// saveStatus 更新内存状态并持久化，持久化失败记录在 StoreErr 中，不影响在线校验的结果
+func (m *LicenseManager) saveStatus(state *LicenseState) *LicenseStatus {
+	status := m.status(state, false)
+	status.StoreErr = m.save(state)
+	return status
+}
+
+// save 先更新内存状态再写入存储
+func (m *LicenseManager) save(state *LicenseState) error {
+	m.mu.Lock()
+	m.state = state
+	m.mu.Unlock()
+
+	payload, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("[%w]: %v", gocreem.MarshalErr, err)
+	}
+	bs, err := json.Marshal(&licenseCacheFile{Payload: payload, Signature: m.sign(payload)})
+	if err != nil {
+		return fmt.Errorf("[%w]: %v", gocreem.MarshalErr, err)
+	}
+	if err = m.store.Save(m.storeKey(), bs); err != nil {
+		return fmt.Errorf("license cache save: %w", err)
+	}
+	return nil
+}
+
+func (m *LicenseManager) load() (*LicenseState, error) {
+	bs, err := m.store.Load(m.storeKey())
+	if err != nil {
+		return nil, err
+	}
+	file := new(licenseCacheFile)
+	if err = json.Unmarshal(bs, file); err != nil {
+		return nil, fmt.Errorf("[%w]: %v", LicenseCacheTamperedErr, err)
+	}
+	expected, _ := hex.DecodeString(m.sign(file.Payload))
+	actual, err := hex.DecodeString(file.Signature)
+	if err != nil || !hmac.Equal(expected, actual) {
+		return nil, LicenseCacheTamperedErr
+	}
+	state := new(LicenseState)
+	if err = json.Unmarshal(file.Payload, state); err != nil {
+		return nil, fmt.Errorf("[%w]: %v", LicenseCacheTamperedErr, err)
+	}
+	if state.LicenseHash != m.licenseHash() {
+		return nil, LicenseCacheTamperedErr
+	}
+
+	m.mu.Lock()
+	m.state = state
+	m.mu.Unlock()
+	return state, nil
+}
+
+func (m *LicenseManager) sign(payload []byte) string {
+	mac := hmac.New(sha256.New, m.cacheKey)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (m *LicenseManager) licenseHash() string {
+	sum := sha256.Sum256([]byte(m.licenseKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func (m *LicenseManager) storeKey() string {
+	return "license-" + m.licenseHash()[:16] + ".json"
+}
+
+// licenseInvalidErrorCodes 明确表示授权无效的错误码，收到时覆盖缓存
+var licenseInvalidErrorCodes = map[string]bool{
+	"license_invalid":  true,
+	"license_expired":  true,
+	"license_revoked":  true,
+	"license_disabled": true,
+}
+
+// isAuthoritativeLicenseResponse 是否为确定的校验结果。
+// 401/403/404 多为 API 密钥轮换或配置错误，不能据此判定授权失效
+func isAuthoritativeLicenseResponse(rsp *LicenseValidateResponse) bool {
+	if rsp.Code == gocreem.Success {
+		return true
+	}
+	return rsp.ErrorResponse != nil && licenseInvalidErrorCodes[rsp.ErrorResponse.Code]
+}
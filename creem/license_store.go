@@ -0,0 +1,88 @@
+package creem
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// LicenseStore 授权缓存存储，Load 在记录不存在时返回 LicenseCacheMissErr
+type LicenseStore interface {
+	Load(key string) ([]byte, error)
+	Save(key string, data []byte) error
+}
+
+// FileLicenseStore 以文件形式保存授权缓存，每个key对应 Dir 下的一个文件
+type FileLicenseStore struct {
+	Dir string
+}
+
+// NewFileLicenseStore 创建文件存储，dir 为空时使用用户配置目录下的 creem 目录
+func NewFileLicenseStore(dir string) (*FileLicenseStore, error) {
+	if dir == "" {
+		base, err := os.UserConfigDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(base, "creem")
+	}
+	return &FileLicenseStore{Dir: dir}, nil
+}
+
+func (s *FileLicenseStore) Load(key string) ([]byte, error) {
+	bs, err := os.ReadFile(filepath.Join(s.Dir, key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, LicenseCacheMissErr
+	}
+	return bs, err
+}
+
+// Save 先写临时文件再重命名，避免写入中断留下半个文件
+func (s *FileLicenseStore) Save(key string, data []byte) error {
+	if err := os.MkdirAll(s.Dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.Dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(s.Dir, key))
+}
+
+// MemoryLicenseStore 内存存储，进程退出后缓存丢失
+type MemoryLicenseStore struct {
+	mu   sync.RWMutex
+	data map[string][]byte
+}
+
+func NewMemoryLicenseStore() *MemoryLicenseStore {
+	return &MemoryLicenseStore{data: make(map[string][]byte)}
+}
+
+func (s *MemoryLicenseStore) Load(key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	bs, ok := s.data[key]
+	if !ok {
+		return nil, LicenseCacheMissErr
+	}
+	return append([]byte(nil), bs...), nil
+}
+
+func (s *MemoryLicenseStore) Save(key string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = append([]byte(nil), data...)
+	return nil
+}
@@ -0,0 +1,197 @@
+package creem
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+
+	"github.com/cloud-evan/gocreem"
+)
+
+// RedactedValue 脱敏后的替换值
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactFields 默认脱敏的 JSON 字段和请求头，不区分大小写
+var DefaultRedactFields = []string{
+	"license_key",
+	"email",
+	"card_number",
+	"card_cvc",
+	"cvc",
+	"cvv",
+	"address",
+	"x-api-key",
+}
+
+// WithSlog 使用 log/slog 输出结构化日志，请求和响应为 Debug 级别，失败为 Warn 级别，
+// 日志级别由 slog.Handler 控制，不受 DebugSwitch 影响
+func WithSlog(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.pending.slogger = logger
+	}
+}
+
+// WithRedactFields 替换默认脱敏字段，可传 append(creem.DefaultRedactFields, "name") 追加，
+// 不传参数则关闭脱敏。对 xlog 和 slog 日志都生效
+func WithRedactFields(fields ...string) Option {
+	return func(c *Client) {
+		c.pending.redact = newRedactor(fields)
+	}
+}
+
+// redactor 按字段名脱敏 JSON 和请求头
+type redactor map[string]struct{}
+
+func newRedactor(fields []string) redactor {
+	r := make(redactor, len(fields))
+	for _, f := range fields {
+		r[strings.ToLower(f)] = struct{}{}
+	}
+	return r
+}
+
+func (r redactor) has(field string) bool {
+	_, ok := r[strings.ToLower(field)]
+	return ok
+}
+
+// body 返回脱敏后的 JSON，非 JSON 内容原样返回
+func (r redactor) body(bs []byte) string {
+	if len(r) == 0 || len(bs) == 0 {
+		return string(bs)
+	}
+	dec := json.NewDecoder(bytes.NewReader(bs))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return string(bs)
+	}
+	out, err := json.Marshal(r.value(v))
+	if err != nil {
+		return string(bs)
+	}
+	return string(out)
+}
+
+func (r redactor) value(v any) any {
+	switch vv := v.(type) {
+	case map[string]any:
+		for k, val := range vv {
+			if r.has(k) {
+				vv[k] = RedactedValue
+			} else {
+				vv[k] = r.value(val)
+			}
+		}
+	case []any:
+		for i, val := range vv {
+			vv[i] = r.value(val)
+		}
+	}
+	return v
+}
+
+// headers 返回自定义请求头，敏感值已脱敏
+func (r redactor) headers(h map[string]string) map[string]string {
+	out := make(map[string]string, len(h))
+	for k, v := range h {
+		if r.has(k) {
+			v = RedactedValue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// logRequest 输出请求日志，slog 未开启 Debug 级别时不序列化请求体
+func (c *Client) logRequest(ctx context.Context, cfg *clientConfig, co *callOptions, info *RequestInfo, url string, body interface{}) {
+	debug := c.DebugSwitch == gocreem.DebugOn
+	slogOn := cfg.slogger != nil && cfg.slogger.Enabled(ctx, slog.LevelDebug)
+	if !debug && !slogOn {
+		return
+	}
+
+	var bodyStr string
+	if body != nil {
+		bodyBytes, _ := json.Marshal(body)
+		bodyStr = cfg.redact.body(bodyBytes)
+	}
+
+	if debug {
+		if body != nil {
+			cfg.logger.Debugf("Creem_Request: %s %s, Body: %s", info.Method, url, bodyStr)
+		} else {
+			cfg.logger.Debugf("Creem_Request: %s %s", info.Method, url)
+		}
+	}
+
+	if slogOn {
+		attrs := []slog.Attr{
+			slog.String("operation", info.Operation),
+			slog.String("method", info.Method),
+			slog.String("path", info.Path),
+		}
+		if info.ResourceID != "" {
+			attrs = append(attrs, slog.String("resource_id", info.ResourceID))
+		}
+		if headers := requestHeaders(cfg, co); len(headers) > 0 {
+			attrs = append(attrs, slog.Any("headers", cfg.redact.headers(headers)))
+		}
+		if body != nil {
+			attrs = append(attrs, slog.String("body", bodyStr))
+		}
+		cfg.slogger.LogAttrs(ctx, slog.LevelDebug, "creem request", attrs...)
+	}
+}
+
+// requestHeaders 合并客户端和本次调用的自定义请求头，本次调用优先
+func requestHeaders(cfg *clientConfig, co *callOptions) map[string]string {
+	if len(cfg.headerKeyMap) == 0 && len(co.headers) == 0 {
+		return nil
+	}
+	headers := make(map[string]string, len(cfg.headerKeyMap)+len(co.headers))
+	for k, v := range cfg.headerKeyMap {
+		headers[k] = v
+	}
+	for k := range co.headers {
+		headers[k] = co.headers.Get(k)
+	}
+	return headers
+}
+
+// logResponse 输出响应日志，info 已包含状态码、耗时和错误
+func (c *Client) logResponse(ctx context.Context, cfg *clientConfig, info *RequestInfo, bs []byte) {
+	if c.DebugSwitch == gocreem.DebugOn && info.Err == nil {
+		cfg.logger.Debugf("Creem_Response: %s", cfg.redact.body(bs))
+	}
+
+	if cfg.slogger == nil {
+		return
+	}
+	level := slog.LevelDebug
+	if info.Err != nil || info.StatusCode >= 400 {
+		level = slog.LevelWarn
+	}
+	if !cfg.slogger.Enabled(ctx, level) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("operation", info.Operation),
+		slog.String("method", info.Method),
+		slog.String("path", info.Path),
+		slog.Int("status", info.StatusCode),
+		slog.Duration("duration", info.Duration),
+	}
+	if info.Retries > 0 {
+		attrs = append(attrs, slog.Int("retries", info.Retries))
+	}
+	if info.Err != nil {
+		attrs = append(attrs, slog.String("error", info.Err.Error()))
+	}
+	if len(bs) > 0 {
+		attrs = append(attrs, slog.String("body", cfg.redact.body(bs)))
+	}
+	cfg.slogger.LogAttrs(ctx, level, "creem response", attrs...)
+}
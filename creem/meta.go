@@ -0,0 +1,105 @@
+package creem
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cloud-evan/gocreem"
+)
+
+// 默认保留到 ResponseMeta.Header 的响应头
+var defaultMetaHeaders = []string{
+	"X-Request-Id",
+	"Request-Id",
+	"X-RateLimit-Limit",
+	"X-RateLimit-Remaining",
+	"X-RateLimit-Reset",
+	"Retry-After",
+}
+
+// ResponseMeta 响应元信息，向 Creem 提交工单时请附上 RequestID
+type ResponseMeta struct {
+	StatusCode         int           // HTTP 状态码
+	Header             http.Header   // 选取的响应头，见 WithResponseHeaders
+	RequestID          string        // X-Request-Id
+	RateLimitLimit     int           // X-RateLimit-Limit，-1 表示未返回
+	RateLimitRemaining int           // X-RateLimit-Remaining，-1 表示未返回
+	RateLimitReset     time.Time     // X-RateLimit-Reset，未返回时为零值
+	Latency            time.Duration // 含重试在内的总耗时
+	Attempts           int           // 请求次数，1 表示未重试
+}
+
+// ResponseError 响应体无法解析时返回（如网关返回的 HTML），携带响应元信息，
+// 可用 errors.As 取出 RequestID，errors.Is(err, gocreem.UnmarshalErr) 仍然成立
+type ResponseError struct {
+	Meta *ResponseMeta
+	Err  error
+}
+
+func (e *ResponseError) Error() string {
+	if e.Meta == nil {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v, status: %d, request_id: %s", e.Err, e.Meta.StatusCode, e.Meta.RequestID)
+}
+
+func (e *ResponseError) Unwrap() error { return e.Err }
+
+// unmarshalResponseErr 包装响应体解析错误，保留元信息
+func unmarshalResponseErr(meta *ResponseMeta, bs []byte, err error) error {
+	return &ResponseError{Meta: meta, Err: fmt.Errorf("[%w]: %v, bytes: %s", gocreem.UnmarshalErr, err, string(bs))}
+}
+
+// WithResponseHeaders 额外保留到 ResponseMeta.Header 的响应头
+func WithResponseHeaders(headers ...string) Option {
+	return func(c *Client) {
+		c.pending.metaHeaders = append(c.pending.metaHeaders, headers...)
+	}
+}
+
+// newResponseMeta 从响应中提取元信息
+func (cfg *clientConfig) newResponseMeta(res *http.Response, attempts int, latency time.Duration) *ResponseMeta {
+	meta := &ResponseMeta{
+		StatusCode:         res.StatusCode,
+		Header:             make(http.Header),
+		RateLimitLimit:     -1,
+		RateLimitRemaining: -1,
+		Latency:            latency,
+		Attempts:           attempts,
+	}
+	for _, list := range [][]string{defaultMetaHeaders, cfg.metaHeaders} {
+		for _, k := range list {
+			if v := res.Header.Values(k); len(v) > 0 {
+				meta.Header[http.CanonicalHeaderKey(k)] = v
+			}
+		}
+	}
+
+	meta.RequestID = res.Header.Get("X-Request-Id")
+	if meta.RequestID == "" {
+		meta.RequestID = res.Header.Get("Request-Id")
+	}
+	if v, err := strconv.Atoi(res.Header.Get("X-RateLimit-Limit")); err == nil {
+		meta.RateLimitLimit = v
+	}
+	if v, err := strconv.Atoi(res.Header.Get("X-RateLimit-Remaining")); err == nil {
+		meta.RateLimitRemaining = v
+	}
+	meta.RateLimitReset = parseRateLimitReset(res.Header.Get("X-RateLimit-Reset"), time.Now())
+	return meta
+}
+
+// parseRateLimitReset 兼容 Unix 时间戳和距重置的秒数两种格式
+func parseRateLimitReset(v string, now time.Time) time.Time {
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return time.Time{}
+	}
+	// 大于一年的秒数视为 Unix 时间戳
+	if n > 365*24*3600 {
+		return time.Unix(n, 0)
+	}
+	return now.Add(time.Duration(n) * time.Second)
+}
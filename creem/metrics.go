@@ -0,0 +1,22 @@
+package creem
+
+import "time"
+
+// MetricsCollector 客户端指标接口，不依赖任何监控库，Prometheus 实现见 creem/prometheus
+type MetricsCollector interface {
+	// RequestStarted 请求开始（含重试在内只调用一次）
+	RequestStarted(operation, method string)
+	// RequestFinished 请求结束，statusCode 为 0 表示网络错误
+	RequestFinished(operation, method string, statusCode int, duration time.Duration, err error)
+	// RequestRetried 第 attempt 次重试前调用，从 1 开始
+	RequestRetried(operation, method string, attempt int)
+	// RateLimitWaited 收到 429 后按 Retry-After 等待
+	RateLimitWaited(operation string, wait time.Duration)
+}
+
+// WithMetrics 设置指标收集器
+func WithMetrics(collector MetricsCollector) Option {
+	return func(c *Client) {
+		c.pending.metrics = collector
+	}
+}
@@ -1,6 +1,7 @@
 package creem
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -9,6 +10,7 @@ type BaseResponse struct {
 	Code          int            `json:"-"`
 	Error         string         `json:"-"`
 	ErrorResponse *ErrorResponse `json:"-"`
+	Meta          *ResponseMeta  `json:"-"`
 }
 
 // 错误响应
@@ -24,20 +26,31 @@ type Product struct {
 	Name        string                 `json:"name"`
 	Description string                 `json:"description"`
 	Type        string                 `json:"type"`
-	Price       float64                `json:"price"`
-	Currency    string                 `json:"currency"`
+	Price       float64                `json:"price"`    // 默认价格，多价格时见 Prices
+	Currency    string                 `json:"currency"` // 默认价格的货币
+	Prices      []ProductPrice         `json:"prices,omitempty"`
 	Active      bool                   `json:"active"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
 	CreatedAt   time.Time              `json:"created_at"`
 	UpdatedAt   time.Time              `json:"updated_at"`
 }
 
+// ProductPrice 产品在某个货币和计费周期下的价格，一次性产品 BillingCycle 为空
+type ProductPrice struct {
+	ID           string  `json:"id,omitempty"`
+	Amount       float64 `json:"amount"`
+	Currency     string  `json:"currency"`
+	BillingCycle string  `json:"billing_cycle,omitempty"`
+	Default      bool    `json:"default,omitempty"`
+}
+
 type ProductCreateRequest struct {
 	Name        string                 `json:"name"`
 	Description string                 `json:"description"`
 	Type        string                 `json:"type"`
-	Price       float64                `json:"price"`
-	Currency    string                 `json:"currency"`
+	Price       float64                `json:"price,omitempty"`
+	Currency    string                 `json:"currency,omitempty"`
+	Prices      []ProductPrice         `json:"prices,omitempty"` // 设置后可不填 Price/Currency
 	Active      bool                   `json:"active"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
 }
@@ -48,7 +61,8 @@ type ProductUpdateRequest struct {
 	Type        string                 `json:"type,omitempty"`
 	Price       float64                `json:"price,omitempty"`
 	Currency    string                 `json:"currency,omitempty"`
-	Active      bool                   `json:"active,omitempty"`
+	Prices      []ProductPrice         `json:"prices,omitempty"` // 设置后整体替换产品的价格列表
+	Active      *bool                  `json:"active,omitempty"` // 为空时不修改，可传 false 停用产品
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
 }
 
@@ -87,6 +101,8 @@ type CheckoutSession struct {
 	CancelURL       string                 `json:"cancel_url"`
 	SuccessURL      string                 `json:"success_url"`
 	PaymentMethodID string                 `json:"payment_method_id,omitempty"`
+	Order           *Order                 `json:"order,omitempty"`        // 支付完成后生成的订单
+	Subscription    *Subscription          `json:"subscription,omitempty"` // 订阅类产品支付完成后生成的订阅
 	Metadata        map[string]interface{} `json:"metadata,omitempty"`
 	CreatedAt       time.Time              `json:"created_at"`
 	UpdatedAt       time.Time              `json:"updated_at"`
@@ -96,6 +112,10 @@ type CheckoutSession struct {
 type CheckoutSessionCreateRequest struct {
 	ProductID       string                 `json:"product_id"`
 	CustomerID      string                 `json:"customer_id,omitempty"`
+	Customer        *CheckoutCustomer      `json:"customer,omitempty"`      // 预填客户信息
+	RequestID       string                 `json:"request_id,omitempty"`    // 业务侧请求ID，会回传到成功跳转URL和Webhook
+	Units           int                    `json:"units,omitempty"`         // 购买数量，如席位数
+	DiscountCode    string                 `json:"discount_code,omitempty"` // 预填优惠码
 	Amount          float64                `json:"amount,omitempty"`
 	Currency        string                 `json:"currency,omitempty"`
 	ReturnURL       string                 `json:"return_url"`
@@ -105,6 +125,12 @@ type CheckoutSessionCreateRequest struct {
 	Metadata        map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// CheckoutCustomer 结账页预填的客户信息
+type CheckoutCustomer struct {
+	ID    string `json:"id,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
 type CheckoutSessionUpdateRequest struct {
 	Amount          float64                `json:"amount,omitempty"`
 	Currency        string                 `json:"currency,omitempty"`
@@ -163,7 +189,7 @@ type Subscription struct {
 	ID                 string                 `json:"id"`
 	CustomerID         string                 `json:"customer_id"`
 	ProductID          string                 `json:"product_id"`
-	Status             string                 `json:"status"`
+	Status             SubscriptionStatus     `json:"status"`
 	Amount             float64                `json:"amount"`
 	Currency           string                 `json:"currency"`
 	BillingCycle       string                 `json:"billing_cycle"`
@@ -172,11 +198,22 @@ type Subscription struct {
 	CurrentPeriodEnd   time.Time              `json:"current_period_end"`
 	CanceledAt         *time.Time             `json:"canceled_at,omitempty"`
 	EndedAt            *time.Time             `json:"ended_at,omitempty"`
+	CancelAtPeriodEnd  bool                   `json:"cancel_at_period_end,omitempty"`
+	CancelReason       string                 `json:"cancel_reason,omitempty"`
+	Items              []SubscriptionItem     `json:"items,omitempty"`
 	Metadata           map[string]interface{} `json:"metadata,omitempty"`
 	CreatedAt          time.Time              `json:"created_at"`
 	UpdatedAt          time.Time              `json:"updated_at"`
 }
 
+// SubscriptionItem 订阅项，按席位计费时 Units 为席位数
+type SubscriptionItem struct {
+	ID        string `json:"id"`
+	ProductID string `json:"product_id"`
+	PriceID   string `json:"price_id,omitempty"`
+	Units     int    `json:"units"`
+}
+
 type SubscriptionCreateRequest struct {
 	CustomerID   string                 `json:"customer_id"`
 	ProductID    string                 `json:"product_id"`
@@ -188,11 +225,20 @@ type SubscriptionCreateRequest struct {
 }
 
 type SubscriptionUpdateRequest struct {
-	Amount       float64                `json:"amount,omitempty"`
-	Currency     string                 `json:"currency,omitempty"`
-	BillingCycle string                 `json:"billing_cycle,omitempty"`
-	TrialDays    int                    `json:"trial_days,omitempty"`
-	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+	Amount         float64                  `json:"amount,omitempty"`
+	Currency       string                   `json:"currency,omitempty"`
+	BillingCycle   string                   `json:"billing_cycle,omitempty"`
+	TrialDays      int                      `json:"trial_days,omitempty"`
+	Items          []SubscriptionItemUpdate `json:"items,omitempty"`           // 修改订阅项数量，如席位数
+	UpdateBehavior string                   `json:"update_behavior,omitempty"` // UpdateBehaviorProration*，默认由 Creem 决定
+	Metadata       map[string]interface{}   `json:"metadata,omitempty"`
+}
+
+// SubscriptionItemUpdate 修改订阅项，ID 与 ProductID 至少填一个
+type SubscriptionItemUpdate struct {
+	ID        string `json:"id,omitempty"`
+	ProductID string `json:"product_id,omitempty"`
+	Units     int    `json:"units"`
 }
 
 type SubscriptionsListResponse struct {
@@ -226,6 +272,44 @@ type SubscriptionUpgradeResponse struct {
 	Data Subscription `json:"data"`
 }
 
+type SubscriptionCreateResponse struct {
+	BaseResponse
+	Data Subscription `json:"data"`
+}
+
+type SubscriptionCancelRequest struct {
+	Mode   string `json:"mode,omitempty"`   // CancelModeImmediate（默认）或 CancelModeEndOfPeriod
+	Reason string `json:"reason,omitempty"` // 取消原因
+}
+
+// UsageRecord 一个统计周期内的用量
+type UsageRecord struct {
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	Quantity    int64     `json:"quantity"`
+}
+
+type UsageRecordRequest struct {
+	Records []UsageRecord `json:"records"`
+}
+
+type UsageRecordResponse struct {
+	BaseResponse
+	Data struct {
+		Accepted int `json:"accepted"`
+	} `json:"data"`
+}
+
+type SubscriptionPauseResponse struct {
+	BaseResponse
+	Data Subscription `json:"data"`
+}
+
+type SubscriptionResumeResponse struct {
+	BaseResponse
+	Data Subscription `json:"data"`
+}
+
 type SubscriptionCancelResponse struct {
 	BaseResponse
 	Data struct {
@@ -444,6 +528,14 @@ type WebhookUpdateResponse struct {
 	Data Webhook `json:"data"`
 }
 
+// Webhook事件，Object 按 EventType 解析为对应模型
+type WebhookEvent struct {
+	ID        string          `json:"id"`
+	EventType string          `json:"eventType"`
+	CreatedAt int64           `json:"created_at"` // 毫秒时间戳
+	Object    json.RawMessage `json:"object"`
+}
+
 // 发票相关模型
 type Invoice struct {
 	ID             string                 `json:"id"`
@@ -525,15 +617,16 @@ type InvoiceUpdateResponse struct {
 
 // 退款相关模型
 type Refund struct {
-	ID        string                 `json:"id"`
-	OrderID   string                 `json:"order_id"`
-	Amount    float64                `json:"amount"`
-	Currency  string                 `json:"currency"`
-	Status    string                 `json:"status"`
-	Reason    string                 `json:"reason,omitempty"`
-	Metadata  map[string]interface{} `json:"metadata,omitempty"`
-	CreatedAt time.Time              `json:"created_at"`
-	UpdatedAt time.Time              `json:"updated_at"`
+	ID         string                 `json:"id"`
+	OrderID    string                 `json:"order_id"`
+	CustomerID string                 `json:"customer_id,omitempty"`
+	Amount     float64                `json:"amount"`
+	Currency   string                 `json:"currency"`
+	Status     string                 `json:"status"`
+	Reason     string                 `json:"reason,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+	UpdatedAt  time.Time              `json:"updated_at"`
 }
 
 type RefundCreateRequest struct {
@@ -685,41 +778,63 @@ type TransactionsListResponse struct {
 }
 
 // License相关模型
+type LicenseInstance struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type LicenseValidateRequest struct {
 	LicenseKey string `json:"license_key"`
+	InstanceID string `json:"instance_id,omitempty"`
 }
 
 type LicenseValidateResponse struct {
 	BaseResponse
 	Data struct {
-		Valid    bool   `json:"valid"`
-		Message  string `json:"message"`
-		Customer string `json:"customer,omitempty"`
+		Valid           bool             `json:"valid"`
+		Message         string           `json:"message"`
+		Customer        string           `json:"customer,omitempty"`
+		ProductID       string           `json:"product_id,omitempty"`
+		ExpiresAt       *time.Time       `json:"expires_at,omitempty"` // 为空表示永久授权
+		ActivationLimit int              `json:"activation_limit"`     // 可激活设备数，0 表示不限
+		Activation      int              `json:"activation"`           // 已激活设备数
+		Instance        *LicenseInstance `json:"instance,omitempty"`
 	} `json:"data"`
 }
 
 type LicenseActivateRequest struct {
-	LicenseKey string `json:"license_key"`
-	CustomerID string `json:"customer_id"`
+	LicenseKey   string `json:"license_key"`
+	CustomerID   string `json:"customer_id"`
+	InstanceName string `json:"instance_name,omitempty"` // 设备名，如 MachineFingerprint()
+	InstanceID   string `json:"instance_id,omitempty"`   // 已激活过的实例ID，重新激活不占用新席位
 }
 
 type LicenseActivateResponse struct {
 	BaseResponse
 	Data struct {
-		Success bool   `json:"success"`
-		Message string `json:"message"`
+		Success         bool             `json:"success"`
+		Message         string           `json:"message"`
+		ActivationLimit int              `json:"activation_limit"`
+		Activation      int              `json:"activation"`
+		Instance        *LicenseInstance `json:"instance,omitempty"`
 	} `json:"data"`
 }
 
 type LicenseDeactivateRequest struct {
 	LicenseKey string `json:"license_key"`
+	InstanceID string `json:"instance_id,omitempty"`
 }
 
 type LicenseDeactivateResponse struct {
 	BaseResponse
 	Data struct {
-		Success bool   `json:"success"`
-		Message string `json:"message"`
+		Success         bool             `json:"success"`
+		Message         string           `json:"message"`
+		ActivationLimit int              `json:"activation_limit"`
+		Activation      int              `json:"activation"`
+		Instance        *LicenseInstance `json:"instance,omitempty"`
 	} `json:"data"`
 }
 
@@ -758,3 +873,11 @@ type DiscountCodeDetailResponse struct {
 	BaseResponse
 	Data DiscountCode `json:"data"`
 }
+
+type DiscountCodesListResponse struct {
+	BaseResponse
+	Data       []DiscountCode `json:"data"`
+	TotalCount int            `json:"total_count"`
+	Page       int            `json:"page"`
+	Limit      int            `json:"limit"`
+}
@@ -0,0 +1,51 @@
+package creem
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// zeroDecimalCurrencies 没有小数位的货币
+var zeroDecimalCurrencies = map[string]bool{
+	"BIF": true, "CLP": true, "DJF": true, "GNF": true, "ISK": true, "JPY": true,
+	"KMF": true, "KRW": true, "PYG": true, "RWF": true, "UGX": true, "VND": true,
+	"VUV": true, "XAF": true, "XOF": true, "XPF": true,
+}
+
+// CurrencyExponent 货币的小数位数，如 USD 为 2，JPY 为 0
+func CurrencyExponent(currency string) int {
+	if zeroDecimalCurrencies[strings.ToUpper(currency)] {
+		return 0
+	}
+	return 2
+}
+
+// ToMinorUnits 金额转换为最小货币单位（如美分），按十进制字面值四舍五入，避免浮点误差
+func ToMinorUnits(amount float64, currency string) int64 {
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(amount, 'f', -1, 64))
+	return roundRat(r.Mul(r, new(big.Rat).SetInt(pow10(CurrencyExponent(currency)))))
+}
+
+// FormatMinorUnits 最小货币单位格式化为十进制金额，如 1999 USD 为 "19.99"
+func FormatMinorUnits(minor int64, currency string) string {
+	r := new(big.Rat).SetFrac(big.NewInt(minor), pow10(CurrencyExponent(currency)))
+	return r.FloatString(CurrencyExponent(currency))
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// roundRat 四舍五入到整数，.5 远离零
+func roundRat(r *big.Rat) int64 {
+	num := new(big.Int).Abs(r.Num())
+	q, m := new(big.Int).QuoRem(num, r.Denom(), new(big.Int))
+	if m.Mul(m, big.NewInt(2)).Cmp(r.Denom()) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if r.Sign() < 0 {
+		q.Neg(q)
+	}
+	return q.Int64()
+}
@@ -0,0 +1,156 @@
+// Package otel 为 creem.Client 提供 OpenTelemetry 链路追踪和指标
+//
+//	hook, err := otel.NewHook(otel.WithTracerProvider(tp), otel.WithMeterProvider(mp))
+//	client, err := creem.NewClient(apiKey, secretKey, true, creem.WithHook(hook))
+package otel
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/cloud-evan/gocreem/creem"
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ScopeName 追踪和指标使用的 instrumentation scope
+	ScopeName = "github.com/cloud-evan/gocreem/creem/otel"
+
+	// SpanPrefix span 名称前缀，span 名为 creem.<接口名>
+	SpanPrefix = "creem."
+
+	MetricRequestDuration = "creem.client.request.duration"
+	MetricRequestErrors   = "creem.client.request.errors"
+
+	AttrOperation  = attribute.Key("creem.operation")
+	AttrResourceID = attribute.Key("creem.resource_id")
+	AttrRetryCount = attribute.Key("creem.retry_count")
+	AttrMethod     = attribute.Key("http.request.method")
+	AttrStatusCode = attribute.Key("http.response.status_code")
+	AttrErrorType  = attribute.Key("error.type")
+)
+
+type config struct {
+	tp trace.TracerProvider
+	mp metric.MeterProvider
+}
+
+type Option func(*config)
+
+// WithTracerProvider 设置 TracerProvider，默认使用全局 otel.GetTracerProvider()
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		if tp != nil {
+			c.tp = tp
+		}
+	}
+}
+
+// WithMeterProvider 设置 MeterProvider，默认使用全局 otel.GetMeterProvider()
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		if mp != nil {
+			c.mp = mp
+		}
+	}
+}
+
+// Hook 实现 creem.Hook，每次 API 调用生成一个 span，并记录耗时直方图和错误计数
+type Hook struct {
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	errors   metric.Int64Counter
+}
+
+var _ creem.Hook = (*Hook)(nil)
+
+// NewHook 初始化 OpenTelemetry 钩子
+func NewHook(options ...Option) (*Hook, error) {
+	cfg := config{tp: otelapi.GetTracerProvider(), mp: otelapi.GetMeterProvider()}
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	meter := cfg.mp.Meter(ScopeName)
+	duration, err := meter.Float64Histogram(MetricRequestDuration,
+		metric.WithDescription("Duration of Creem API calls"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10))
+	if err != nil {
+		return nil, err
+	}
+	errors, err := meter.Int64Counter(MetricRequestErrors,
+		metric.WithDescription("Failed Creem API calls, by transport error or HTTP status"),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Hook{
+		tracer:   cfg.tp.Tracer(ScopeName),
+		duration: duration,
+		errors:   errors,
+	}, nil
+}
+
+// BeforeRequest 开始 span
+func (h *Hook) BeforeRequest(ctx context.Context, info *creem.RequestInfo) context.Context {
+	attrs := []attribute.KeyValue{
+		AttrOperation.String(info.Operation),
+		AttrMethod.String(info.Method),
+	}
+	if info.ResourceID != "" {
+		attrs = append(attrs, AttrResourceID.String(info.ResourceID))
+	}
+	ctx, _ = h.tracer.Start(ctx, SpanPrefix+info.Operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+	return ctx
+}
+
+// AfterRequest 结束 span 并记录指标
+func (h *Hook) AfterRequest(ctx context.Context, info *creem.RequestInfo) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(AttrRetryCount.Int(info.Retries))
+	if info.StatusCode > 0 {
+		span.SetAttributes(AttrStatusCode.Int(info.StatusCode))
+	}
+
+	attrs := []attribute.KeyValue{
+		AttrOperation.String(info.Operation),
+		AttrMethod.String(info.Method),
+	}
+	if info.StatusCode > 0 {
+		attrs = append(attrs, AttrStatusCode.Int(info.StatusCode))
+	}
+
+	if errType := errorType(info); errType != "" {
+		if info.Err != nil {
+			span.RecordError(info.Err)
+			span.SetStatus(codes.Error, info.Err.Error())
+		} else {
+			span.SetStatus(codes.Error, http.StatusText(info.StatusCode))
+		}
+		span.SetAttributes(AttrErrorType.String(errType))
+		h.errors.Add(ctx, 1, metric.WithAttributes(append(attrs, AttrErrorType.String(errType))...))
+	}
+
+	h.duration.Record(ctx, info.Duration.Seconds(), metric.WithAttributes(attrs...))
+	span.End()
+}
+
+// errorType 网络错误为 "transport"，HTTP 4xx/5xx 为状态码，成功为空
+func errorType(info *creem.RequestInfo) string {
+	if info.Err != nil {
+		return "transport"
+	}
+	if info.StatusCode >= http.StatusBadRequest {
+		return strconv.Itoa(info.StatusCode)
+	}
+	return ""
+}
@@ -0,0 +1,174 @@
+package otel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/cloud-evan/gocreem/creem"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// orderHook 记录调用顺序
+type orderHook struct {
+	name  string
+	mu    *sync.Mutex
+	calls *[]string
+}
+
+func (h orderHook) BeforeRequest(ctx context.Context, info *creem.RequestInfo) context.Context {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	*h.calls = append(*h.calls, "before:"+h.name)
+	return ctx
+}
+
+func (h orderHook) AfterRequest(ctx context.Context, info *creem.RequestInfo) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	*h.calls = append(*h.calls, "after:"+h.name)
+}
+
+func newTestServer(t *testing.T, status int) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"id":"prod_123"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestHook(t *testing.T) (*Hook, *tracetest.InMemoryExporter, *sdkmetric.ManualReader) {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	hook, err := NewHook(WithTracerProvider(tp), WithMeterProvider(mp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hook, exporter, reader
+}
+
+func TestHookOrder(t *testing.T) {
+	srv := newTestServer(t, http.StatusOK)
+	var (
+		mu    sync.Mutex
+		calls []string
+	)
+	client, err := creem.NewClient("api_key", "secret_key", true,
+		creem.WithProxyUrl(srv.URL),
+		creem.WithHook(orderHook{name: "a", mu: &mu, calls: &calls}),
+		creem.WithHook(orderHook{name: "b", mu: &mu, calls: &calls}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.GetProduct(context.Background(), "prod_123"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"before:a", "before:b", "after:b", "after:a"}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Fatalf("calls = %v, want %v", calls, want)
+		}
+	}
+}
+
+func TestHookSpan(t *testing.T) {
+	srv := newTestServer(t, http.StatusOK)
+	hook, exporter, _ := newTestHook(t)
+	client, err := creem.NewClient("api_key", "secret_key", true, creem.WithProxyUrl(srv.URL), creem.WithHook(hook))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.GetProduct(context.Background(), "prod_123"); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "creem.GetProduct" {
+		t.Errorf("span name = %q", span.Name)
+	}
+	if span.SpanKind != trace.SpanKindClient {
+		t.Errorf("span kind = %v", span.SpanKind)
+	}
+	if span.Status.Code == codes.Error {
+		t.Errorf("unexpected error status %v", span.Status)
+	}
+	attrs := attribute.NewSet(span.Attributes...)
+	for key, want := range map[attribute.Key]attribute.Value{
+		AttrOperation:  attribute.StringValue("GetProduct"),
+		AttrMethod:     attribute.StringValue(http.MethodGet),
+		AttrResourceID: attribute.StringValue("prod_123"),
+		AttrStatusCode: attribute.IntValue(http.StatusOK),
+		AttrRetryCount: attribute.IntValue(0),
+	} {
+		if got, ok := attrs.Value(key); !ok || got != want {
+			t.Errorf("attribute %s = %v, want %v", key, got.Emit(), want.Emit())
+		}
+	}
+}
+
+func TestHookErrorSpanAndMetrics(t *testing.T) {
+	srv := newTestServer(t, http.StatusNotFound)
+	hook, exporter, reader := newTestHook(t)
+	client, err := creem.NewClient("api_key", "secret_key", true, creem.WithProxyUrl(srv.URL), creem.WithHook(hook))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.GetProduct(context.Background(), "prod_404"); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if spans[0].Status.Code != codes.Error {
+		t.Errorf("span status = %v, want error", spans[0].Status)
+	}
+	attrs := attribute.NewSet(spans[0].Attributes...)
+	if got, _ := attrs.Value(AttrErrorType); got.AsString() != "404" {
+		t.Errorf("error.type = %q, want 404", got.AsString())
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err = reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	found := map[string]bool{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			found[m.Name] = true
+			if m.Name == MetricRequestErrors {
+				sum := m.Data.(metricdata.Sum[int64])
+				if len(sum.DataPoints) != 1 || sum.DataPoints[0].Value != 1 {
+					t.Errorf("errors data points = %+v", sum.DataPoints)
+				}
+			}
+		}
+	}
+	for _, name := range []string{MetricRequestDuration, MetricRequestErrors} {
+		if !found[name] {
+			t.Errorf("metric %s not recorded", name)
+		}
+	}
+}
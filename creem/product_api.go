@@ -14,7 +14,7 @@ import (
 
 // CreateProduct 创建产品
 // 文档：https://docs.creem.io/api-reference/product#create-product
-func (c *Client) CreateProduct(ctx context.Context, req *ProductCreateRequest) (rsp *ProductCreateResponse, err error) {
+func (c *Client) CreateProduct(ctx context.Context, req *ProductCreateRequest, opts ...CallOption) (rsp *ProductCreateResponse, err error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
@@ -29,28 +29,35 @@ func (c *Client) CreateProduct(ctx context.Context, req *ProductCreateRequest) (
 	if req.Type == "" {
 		return nil, errors.New("product type is required")
 	}
-	if req.Price <= 0 {
-		return nil, MissPriceErr
-	}
-	if req.Currency == "" {
-		return nil, MissCurrencyErr
+	if len(req.Prices) > 0 {
+		if err = validateProductPrices(req.Prices); err != nil {
+			return nil, err
+		}
+	} else {
+		if req.Price <= 0 {
+			return nil, MissPriceErr
+		}
+		if req.Currency == "" {
+			return nil, MissCurrencyErr
+		}
 	}
 
-	res, bs, err := c.doCreemPost(ctx, req, productCreate)
+	meta, bs, err := c.doCreemPost(ctx, apiOp{Name: "CreateProduct"}, req, productCreate, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	rsp = &ProductCreateResponse{BaseResponse: BaseResponse{Code: gocreem.Success}}
-	if err = json.Unmarshal(bs, rsp); err != nil {
-		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gocreem.UnmarshalErr, err, string(bs))
-	}
-
-	if res.StatusCode != http.StatusCreated {
-		rsp.Code = res.StatusCode
+	rsp = &ProductCreateResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusCreated {
+		rsp.Code = meta.StatusCode
 		rsp.Error = string(bs)
 		rsp.ErrorResponse = new(ErrorResponse)
 		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, unmarshalResponseErr(meta, bs, err)
 	}
 
 	return rsp, nil
@@ -58,27 +65,28 @@ func (c *Client) CreateProduct(ctx context.Context, req *ProductCreateRequest) (
 
 // GetProduct 获取产品详情
 // 文档：https://docs.creem.io/api-reference/product#get-product
-func (c *Client) GetProduct(ctx context.Context, productID string) (rsp *ProductDetailResponse, err error) {
+func (c *Client) GetProduct(ctx context.Context, productID string, opts ...CallOption) (rsp *ProductDetailResponse, err error) {
 	if productID == "" {
 		return nil, MissProductIdErr
 	}
 
 	path := fmt.Sprintf(productDetail, productID)
-	res, bs, err := c.doCreemGet(ctx, path)
+	meta, bs, err := c.doCreemGet(ctx, apiOp{Name: "GetProduct", ResourceID: productID}, path, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	rsp = &ProductDetailResponse{BaseResponse: BaseResponse{Code: gocreem.Success}}
-	if err = json.Unmarshal(bs, rsp); err != nil {
-		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gocreem.UnmarshalErr, err, string(bs))
-	}
-
-	if res.StatusCode != http.StatusOK {
-		rsp.Code = res.StatusCode
+	rsp = &ProductDetailResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusOK {
+		rsp.Code = meta.StatusCode
 		rsp.Error = string(bs)
 		rsp.ErrorResponse = new(ErrorResponse)
 		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, unmarshalResponseErr(meta, bs, err)
 	}
 
 	return rsp, nil
@@ -86,7 +94,7 @@ func (c *Client) GetProduct(ctx context.Context, productID string) (rsp *Product
 
 // ListProducts 获取产品列表
 // 文档：https://docs.creem.io/api-reference/product#list-products
-func (c *Client) ListProducts(ctx context.Context, params *ListParams) (rsp *ProductsListResponse, err error) {
+func (c *Client) ListProducts(ctx context.Context, params *ListParams, opts ...CallOption) (rsp *ProductsListResponse, err error) {
 	if params == nil {
 		params = &ListParams{}
 	}
@@ -108,21 +116,62 @@ func (c *Client) ListProducts(ctx context.Context, params *ListParams) (rsp *Pro
 		path += "?" + queryParams.Encode()
 	}
 
-	res, bs, err := c.doCreemGet(ctx, path)
+	meta, bs, err := c.doCreemGet(ctx, apiOp{Name: "ListProducts"}, path, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	rsp = &ProductsListResponse{BaseResponse: BaseResponse{Code: gocreem.Success}}
+	rsp = &ProductsListResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusOK {
+		rsp.Code = meta.StatusCode
+		rsp.Error = string(bs)
+		rsp.ErrorResponse = new(ErrorResponse)
+		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
 	if err = json.Unmarshal(bs, rsp); err != nil {
-		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gocreem.UnmarshalErr, err, string(bs))
+		return nil, unmarshalResponseErr(meta, bs, err)
+	}
+
+	return rsp, nil
+}
+
+// UpdateProduct 更新产品，设置 Prices 时整体替换价格列表
+// 文档：https://docs.creem.io/api-reference/product#update-product
+func (c *Client) UpdateProduct(ctx context.Context, productID string, req *ProductUpdateRequest, opts ...CallOption) (rsp *ProductUpdateResponse, err error) {
+	if productID == "" {
+		return nil, MissProductIdErr
+	}
+	if req == nil {
+		return nil, errors.New("request is nil")
 	}
 
-	if res.StatusCode != http.StatusOK {
-		rsp.Code = res.StatusCode
+	// 参数校验
+	if req.Price < 0 {
+		return nil, MissPriceErr
+	}
+	if err = validateProductPrices(req.Prices); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf(productUpdate, productID)
+	meta, bs, err := c.doCreemPost(ctx, apiOp{Name: "UpdateProduct", ResourceID: productID}, req, path, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &ProductUpdateResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusOK {
+		rsp.Code = meta.StatusCode
 		rsp.Error = string(bs)
 		rsp.ErrorResponse = new(ErrorResponse)
 		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, unmarshalResponseErr(meta, bs, err)
 	}
 
 	return rsp, nil
@@ -0,0 +1,89 @@
+package creem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cloud-evan/gocreem"
+)
+
+func TestUpdateProduct(t *testing.T) {
+	var method, path string
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		bs, _ := io.ReadAll(r.Body)
+		body = nil
+		_ = json.Unmarshal(bs, &body)
+		if r.URL.Path == "/v1/products/prod_missing" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"product not found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"id":"prod_1","name":"Team","active":false}}`)
+	}))
+	defer srv.Close()
+	client, err := NewClient("key", "secret", true, WithProxyUrl(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	active := false
+	rsp, err := client.UpdateProduct(ctx, "prod_1", &ProductUpdateRequest{Active: &active})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method != http.MethodPost || path != "/v1/products/prod_1" {
+		t.Fatalf("unexpected request %s %s", method, path)
+	}
+	// 只发送设置的字段，false 不能被省略
+	if len(body) != 1 || body["active"] != false {
+		t.Fatalf("expected body {\"active\":false}, got %v", body)
+	}
+	if rsp.Code != gocreem.Success || rsp.Data.ID != "prod_1" || rsp.Data.Active {
+		t.Fatalf("unexpected response %+v", rsp)
+	}
+
+	rsp, err = client.UpdateProduct(ctx, "prod_missing", &ProductUpdateRequest{Name: "Team"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsp.Code != http.StatusNotFound || rsp.ErrorResponse == nil {
+		t.Fatalf("expected 404 with error response, got %+v", rsp)
+	}
+}
+
+func TestUpdateProductValidation(t *testing.T) {
+	client, err := NewClient("key", "secret", true, WithProxyUrl("http://127.0.0.1:0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		id   string
+		req  *ProductUpdateRequest
+		err  error
+	}{
+		{name: "missing id", req: &ProductUpdateRequest{}, err: MissProductIdErr},
+		{name: "negative price", id: "prod_1", req: &ProductUpdateRequest{Price: -1}, err: MissPriceErr},
+		{name: "price without currency", id: "prod_1", req: &ProductUpdateRequest{Prices: []ProductPrice{{Amount: 10}}}, err: MissCurrencyErr},
+		{name: "duplicate price", id: "prod_1", req: &ProductUpdateRequest{Prices: []ProductPrice{
+			{Amount: 10, Currency: "USD", BillingCycle: BillingCycleMonthly},
+			{Amount: 12, Currency: "usd", BillingCycle: BillingCycleMonthly},
+		}}, err: DuplicatePriceErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := client.UpdateProduct(context.Background(), tt.id, tt.req); !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package creem
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PriceFor 查找指定货币和计费周期的价格，货币不区分大小写。
+// 有多个匹配时优先 Default；未设置 Prices 时使用 Price/Currency，此时不区分计费周期
+func (p *Product) PriceFor(currency, billingCycle string) (*ProductPrice, error) {
+	var found *ProductPrice
+	for i := range p.Prices {
+		price := &p.Prices[i]
+		if !strings.EqualFold(price.Currency, currency) || price.BillingCycle != billingCycle {
+			continue
+		}
+		if found == nil || (price.Default && !found.Default) {
+			found = price
+		}
+	}
+	if found != nil {
+		return found, nil
+	}
+	if len(p.Prices) == 0 && p.Currency != "" && strings.EqualFold(p.Currency, currency) {
+		return &ProductPrice{Amount: p.Price, Currency: p.Currency, BillingCycle: billingCycle, Default: true}, nil
+	}
+	return nil, fmt.Errorf("[%w]: product %s, %s %s", PriceNotFoundErr, p.ID, currency, billingCycle)
+}
+
+// validateProductPrices 校验金额、货币，同一货币和计费周期只能有一个价格
+func validateProductPrices(prices []ProductPrice) error {
+	seen := make(map[string]bool, len(prices))
+	for _, price := range prices {
+		if price.Amount <= 0 {
+			return MissPriceErr
+		}
+		if price.Currency == "" {
+			return MissCurrencyErr
+		}
+		key := strings.ToUpper(price.Currency) + "|" + price.BillingCycle
+		if seen[key] {
+			return fmt.Errorf("[%w]: %s %s", DuplicatePriceErr, price.Currency, price.BillingCycle)
+		}
+		seen[key] = true
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+// Package prometheus 提供 creem.MetricsCollector 的 Prometheus 实现
+//
+//	collector := prometheus.NewCollector(prometheus.WithConstLabels(map[string]string{"client": "billing"}))
+//	registry.MustRegister(collector)
+//	client, err := creem.NewClient(apiKey, secretKey, true, creem.WithMetrics(collector))
+package prometheus
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/cloud-evan/gocreem/creem"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	defaultNamespace = "creem"
+	defaultSubsystem = "client"
+
+	// StatusError 网络错误时 status 标签的取值
+	StatusError = "error"
+)
+
+type config struct {
+	namespace   string
+	subsystem   string
+	constLabels prom.Labels
+	buckets     []float64
+}
+
+type Option func(*config)
+
+// WithNamespace 指标名前缀，默认 creem
+func WithNamespace(namespace string) Option {
+	return func(c *config) {
+		c.namespace = namespace
+	}
+}
+
+// WithSubsystem 指标名第二段，默认 client
+func WithSubsystem(subsystem string) Option {
+	return func(c *config) {
+		c.subsystem = subsystem
+	}
+}
+
+// WithConstLabels 固定标签，同一进程内有多个 creem.Client 时用于区分
+func WithConstLabels(labels map[string]string) Option {
+	return func(c *config) {
+		c.constLabels = labels
+	}
+}
+
+// WithBuckets 请求耗时直方图的桶，单位秒
+func WithBuckets(buckets []float64) Option {
+	return func(c *config) {
+		if len(buckets) > 0 {
+			c.buckets = buckets
+		}
+	}
+}
+
+// Collector 同时实现 creem.MetricsCollector 和 prometheus.Collector，
+// 每个 creem.Client 使用一个 Collector
+type Collector struct {
+	requests      *prom.CounterVec
+	duration      *prom.HistogramVec
+	inFlight      *prom.GaugeVec
+	retries       *prom.CounterVec
+	rateLimitWait *prom.HistogramVec
+}
+
+var (
+	_ creem.MetricsCollector = (*Collector)(nil)
+	_ prom.Collector         = (*Collector)(nil)
+)
+
+// NewCollector 初始化 Prometheus 指标收集器，需自行注册到 Registry
+func NewCollector(options ...Option) *Collector {
+	cfg := config{
+		namespace: defaultNamespace,
+		subsystem: defaultSubsystem,
+		buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	return &Collector{
+		requests: prom.NewCounterVec(prom.CounterOpts{
+			Namespace:   cfg.namespace,
+			Subsystem:   cfg.subsystem,
+			Name:        "requests_total",
+			Help:        "Creem API calls by operation and HTTP status, retries excluded.",
+			ConstLabels: cfg.constLabels,
+		}, []string{"operation", "status"}),
+		duration: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace:   cfg.namespace,
+			Subsystem:   cfg.subsystem,
+			Name:        "request_duration_seconds",
+			Help:        "Duration of Creem API calls including retries.",
+			ConstLabels: cfg.constLabels,
+			Buckets:     cfg.buckets,
+		}, []string{"operation"}),
+		inFlight: prom.NewGaugeVec(prom.GaugeOpts{
+			Namespace:   cfg.namespace,
+			Subsystem:   cfg.subsystem,
+			Name:        "requests_in_flight",
+			Help:        "Creem API calls currently in progress.",
+			ConstLabels: cfg.constLabels,
+		}, []string{"operation"}),
+		retries: prom.NewCounterVec(prom.CounterOpts{
+			Namespace:   cfg.namespace,
+			Subsystem:   cfg.subsystem,
+			Name:        "retries_total",
+			Help:        "Retried Creem API attempts.",
+			ConstLabels: cfg.constLabels,
+		}, []string{"operation"}),
+		rateLimitWait: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace:   cfg.namespace,
+			Subsystem:   cfg.subsystem,
+			Name:        "rate_limit_wait_seconds",
+			Help:        "Time spent waiting on Retry-After after a 429 response.",
+			ConstLabels: cfg.constLabels,
+			Buckets:     []float64{0.5, 1, 2, 5, 10, 30, 60},
+		}, []string{"operation"}),
+	}
+}
+
+// Describe 实现 prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prom.Desc) {
+	c.requests.Describe(ch)
+	c.duration.Describe(ch)
+	c.inFlight.Describe(ch)
+	c.retries.Describe(ch)
+	c.rateLimitWait.Describe(ch)
+}
+
+// Collect 实现 prometheus.Collector
+func (c *Collector) Collect(ch chan<- prom.Metric) {
+	c.requests.Collect(ch)
+	c.duration.Collect(ch)
+	c.inFlight.Collect(ch)
+	c.retries.Collect(ch)
+	c.rateLimitWait.Collect(ch)
+}
+
+// RequestStarted 实现 creem.MetricsCollector
+func (c *Collector) RequestStarted(operation, method string) {
+	c.inFlight.WithLabelValues(operation).Inc()
+}
+
+// RequestFinished 实现 creem.MetricsCollector
+func (c *Collector) RequestFinished(operation, method string, statusCode int, duration time.Duration, err error) {
+	c.inFlight.WithLabelValues(operation).Dec()
+	status := StatusError
+	if statusCode > 0 {
+		status = strconv.Itoa(statusCode)
+	}
+	c.requests.WithLabelValues(operation, status).Inc()
+	c.duration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+// RequestRetried 实现 creem.MetricsCollector
+func (c *Collector) RequestRetried(operation, method string, attempt int) {
+	c.retries.WithLabelValues(operation).Inc()
+}
+
+// RateLimitWaited 实现 creem.MetricsCollector
+func (c *Collector) RateLimitWaited(operation string, wait time.Duration) {
+	c.rateLimitWait.WithLabelValues(operation).Observe(wait.Seconds())
+}
@@ -0,0 +1,211 @@
+package creem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/cloud-evan/gocreem"
+)
+
+// UpgradePreviewRequest 升级预览参数，确认后可直接将 SubscriptionUpgradeRequest 传给 UpgradeSubscription
+type UpgradePreviewRequest struct {
+	SubscriptionUpgradeRequest
+	ProrationBasis string    // ProrationByDay（默认）或 ProrationBySecond
+	ProrationDate  time.Time // 按该时间点计算，默认当前时间
+}
+
+// ProrationLineItem 按比例计费明细，Amount 为最小货币单位，负数表示抵扣
+type ProrationLineItem struct {
+	Description string
+	ProductID   string
+	Amount      int64
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+}
+
+// UpgradePreview 升级费用预估，金额均为最小货币单位（如美分），见 FormatMinorUnits
+type UpgradePreview struct {
+	SubscriptionID   string
+	CurrentProductID string
+	NewProductID     string
+	Currency         string
+	ProrationBasis   string
+	ProrationDate    time.Time
+	PeriodStart      time.Time
+	PeriodEnd        time.Time
+	TotalUnits       int64 // 计费周期总天数或秒数
+	RemainingUnits   int64 // 剩余天数或秒数
+	Seats            int64 // 席位数，非席位订阅为 1
+	CurrentAmount    int64 // 当前订阅价格，单价乘以席位数
+	NewAmount        int64 // 目标产品价格，单价乘以席位数
+	LineItems        []ProrationLineItem
+	AmountDue        int64 // 本次应付，负数表示退回到账户余额
+}
+
+// PreviewUpgrade 预估升级订阅本次应付金额，不会修改订阅。
+// 按当前计费周期剩余比例抵扣原价格、收取新价格，结果为估算值，以 Creem 实际扣费为准。
+// 订阅金额和产品价格均视为单价，按席位计费的订阅乘以订阅项的 Units；包含多个订阅项时返回 UnsupportedSubscriptionItemsErr
+func (c *Client) PreviewUpgrade(ctx context.Context, subscriptionID string, req *UpgradePreviewRequest, opts ...CallOption) (preview *UpgradePreview, err error) {
+	if subscriptionID == "" {
+		return nil, MissSubscriptionIdErr
+	}
+	if req == nil {
+		return nil, errors.New("request is nil")
+	}
+
+	// 参数校验
+	if req.NewProductID == "" {
+		return nil, MissProductIdErr
+	}
+	basis := req.ProrationBasis
+	if basis == "" {
+		basis = ProrationByDay
+	}
+	if basis != ProrationByDay && basis != ProrationBySecond {
+		return nil, fmt.Errorf("[%w]: %s", InvalidProrationBasisErr, req.ProrationBasis)
+	}
+
+	// 两次查询共用本次调用的超时，不带幂等键
+	if co := newCallOptions(opts); co.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, co.timeout)
+		defer cancel()
+	}
+	opts = readOptions(opts)
+
+	subRsp, err := c.GetSubscription(ctx, subscriptionID, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if subRsp.Code != gocreem.Success {
+		return nil, fmt.Errorf("preview upgrade: get subscription status %d: %s", subRsp.Code, subRsp.Error)
+	}
+	sub := &subRsp.Data
+	if sub.Status.Valid() && !sub.Status.CanUpgrade() {
+		return nil, fmt.Errorf("[%w]: cannot upgrade subscription %s in status %s", SubscriptionStatusErr, subscriptionID, sub.Status)
+	}
+	if !sub.CurrentPeriodEnd.After(sub.CurrentPeriodStart) {
+		return nil, fmt.Errorf("[%w]: %s - %s", InvalidBillingPeriodErr, sub.CurrentPeriodStart, sub.CurrentPeriodEnd)
+	}
+	if len(sub.Items) > 1 {
+		return nil, fmt.Errorf("[%w]: subscription %s has %d items", UnsupportedSubscriptionItemsErr, subscriptionID, len(sub.Items))
+	}
+
+	prodRsp, err := c.GetProduct(ctx, req.NewProductID, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if prodRsp.Code != gocreem.Success {
+		return nil, fmt.Errorf("preview upgrade: get product status %d: %s", prodRsp.Code, prodRsp.Error)
+	}
+
+	currency := sub.Currency
+	if req.Currency != "" {
+		currency = req.Currency
+	}
+	if !strings.EqualFold(currency, sub.Currency) {
+		return nil, fmt.Errorf("[%w]: subscription %s, upgrade %s", CurrencyMismatchErr, sub.Currency, currency)
+	}
+	newAmount := req.Amount
+	if newAmount <= 0 {
+		// 按订阅的货币和计费周期取目标产品价格
+		billingCycle := sub.BillingCycle
+		if req.BillingCycle != "" {
+			billingCycle = req.BillingCycle
+		}
+		price, err := prodRsp.Data.PriceFor(currency, billingCycle)
+		if err != nil {
+			return nil, err
+		}
+		newAmount = price.Amount
+	}
+
+	at := req.ProrationDate
+	if at.IsZero() {
+		at = time.Now()
+	}
+	return newUpgradePreview(sub, req.NewProductID, newAmount, basis, at), nil
+}
+
+// newUpgradePreview 按剩余比例计算抵扣和新价格，金额以 big.Rat 计算后四舍五入到最小货币单位
+func newUpgradePreview(sub *Subscription, newProductID string, newAmount float64, basis string, at time.Time) *UpgradePreview {
+	seats := int64(1)
+	if len(sub.Items) == 1 && sub.Items[0].Units > 1 {
+		seats = int64(sub.Items[0].Units)
+	}
+	preview := &UpgradePreview{
+		SubscriptionID:   sub.ID,
+		CurrentProductID: sub.ProductID,
+		NewProductID:     newProductID,
+		Currency:         sub.Currency,
+		ProrationBasis:   basis,
+		ProrationDate:    at,
+		PeriodStart:      sub.CurrentPeriodStart,
+		PeriodEnd:        sub.CurrentPeriodEnd,
+		Seats:            seats,
+		CurrentAmount:    ToMinorUnits(sub.Amount, sub.Currency) * seats,
+		NewAmount:        ToMinorUnits(newAmount, sub.Currency) * seats,
+	}
+	preview.TotalUnits, preview.RemainingUnits = prorationUnits(basis, sub.CurrentPeriodStart, sub.CurrentPeriodEnd, at)
+
+	ratio := big.NewRat(preview.RemainingUnits, preview.TotalUnits)
+	credit := roundRat(new(big.Rat).Mul(ratio, new(big.Rat).SetInt64(preview.CurrentAmount)))
+	charge := roundRat(new(big.Rat).Mul(ratio, new(big.Rat).SetInt64(preview.NewAmount)))
+	remainingFrom := at
+	if remainingFrom.Before(sub.CurrentPeriodStart) {
+		remainingFrom = sub.CurrentPeriodStart
+	}
+	preview.LineItems = []ProrationLineItem{
+		{
+			Description: fmt.Sprintf("Unused time on %s", sub.ProductID),
+			ProductID:   sub.ProductID,
+			Amount:      -credit,
+			PeriodStart: remainingFrom,
+			PeriodEnd:   sub.CurrentPeriodEnd,
+		},
+		{
+			Description: fmt.Sprintf("Remaining time on %s", newProductID),
+			ProductID:   newProductID,
+			Amount:      charge,
+			PeriodStart: remainingFrom,
+			PeriodEnd:   sub.CurrentPeriodEnd,
+		},
+	}
+	preview.AmountDue = charge - credit
+	return preview
+}
+
+// prorationUnits 计费周期总单位数和 at 之后的剩余单位数，at 超出周期时剩余为 0 或全部
+func prorationUnits(basis string, start, end, at time.Time) (total, remaining int64) {
+	if at.Before(start) {
+		at = start
+	}
+	if at.After(end) {
+		at = end
+	}
+	if basis == ProrationBySecond {
+		total, remaining = end.Unix()-start.Unix(), end.Unix()-at.Unix()
+	} else {
+		total, remaining = calendarDays(start, end), calendarDays(at, end)
+	}
+	if total == 0 {
+		// 不足一个单位的周期按一个单位计算
+		total = 1
+	}
+	return total, min(remaining, total)
+}
+
+// calendarDays 两个时间之间相差的自然日，以周期结束时间的时区为准
+func calendarDays(from, to time.Time) int64 {
+	loc := to.Location()
+	y1, m1, d1 := from.In(loc).Date()
+	y2, m2, d2 := to.In(loc).Date()
+	// 用 UTC 日期计算，避开夏令时导致的 23/25 小时
+	a := time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC)
+	b := time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC)
+	return int64(b.Sub(a) / (24 * time.Hour))
+}
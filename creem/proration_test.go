@@ -0,0 +1,142 @@
+package creem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewUpgradePreview(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC) // 30 天
+
+	tests := []struct {
+		name      string
+		currency  string
+		current   float64
+		new       float64
+		units     int
+		start     time.Time
+		end       time.Time
+		at        time.Time
+		basis     string
+		remaining int64
+		credit    int64
+		charge    int64
+		due       int64
+	}{
+		{
+			name: "rounds thirds to nearest minor unit", currency: "USD", current: 10, new: 20,
+			at: start.AddDate(0, 0, 20), remaining: 10, credit: 333, charge: 667, due: 334,
+		},
+		{
+			name: "rounds half away from zero", currency: "USD", current: 0.05, new: 0.15,
+			start: start, end: start.AddDate(0, 0, 2), at: start.AddDate(0, 0, 1), remaining: 1, credit: 3, charge: 8, due: 5,
+		},
+		{
+			name: "zero days remaining", currency: "USD", current: 10, new: 20,
+			at: end, remaining: 0, credit: 0, charge: 0, due: 0,
+		},
+		{
+			name: "after period end", currency: "USD", current: 10, new: 20,
+			at: end.Add(time.Hour), remaining: 0, credit: 0, charge: 0, due: 0,
+		},
+		{
+			name: "before period start charges full period", currency: "USD", current: 10, new: 20,
+			at: start.Add(-time.Hour), remaining: 30, credit: 1000, charge: 2000, due: 1000,
+		},
+		{
+			name: "downgrade returns credit", currency: "USD", current: 20, new: 10,
+			at: start.AddDate(0, 0, 15), remaining: 15, credit: 1000, charge: 500, due: -500,
+		},
+		{
+			name: "multiplies by seats", currency: "USD", current: 10, new: 20, units: 3,
+			at: start.AddDate(0, 0, 15), remaining: 15, credit: 1500, charge: 3000, due: 1500,
+		},
+		{
+			name: "zero decimal currency", currency: "JPY", current: 1000, new: 3000,
+			at: start.AddDate(0, 0, 20), remaining: 10, credit: 333, charge: 1000, due: 667,
+		},
+		{
+			name: "per second", currency: "USD", current: 10, new: 20, basis: ProrationBySecond,
+			at: start.Add(20*24*time.Hour + 12*time.Hour), remaining: 9*24*3600 + 12*3600, credit: 317, charge: 633, due: 316,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := &Subscription{
+				ID:                 "sub_1",
+				ProductID:          "prod_basic",
+				Amount:             tt.current,
+				Currency:           tt.currency,
+				CurrentPeriodStart: start,
+				CurrentPeriodEnd:   end,
+			}
+			if !tt.start.IsZero() {
+				sub.CurrentPeriodStart, sub.CurrentPeriodEnd = tt.start, tt.end
+			}
+			if tt.units > 0 {
+				sub.Items = []SubscriptionItem{{ID: "item_1", ProductID: "prod_basic", Units: tt.units}}
+			}
+			basis := tt.basis
+			if basis == "" {
+				basis = ProrationByDay
+			}
+
+			preview := newUpgradePreview(sub, "prod_pro", tt.new, basis, tt.at)
+			if preview.RemainingUnits != tt.remaining {
+				t.Errorf("RemainingUnits = %d, want %d", preview.RemainingUnits, tt.remaining)
+			}
+			if got := -preview.LineItems[0].Amount; got != tt.credit {
+				t.Errorf("credit = %d, want %d", got, tt.credit)
+			}
+			if got := preview.LineItems[1].Amount; got != tt.charge {
+				t.Errorf("charge = %d, want %d", got, tt.charge)
+			}
+			if preview.AmountDue != tt.due {
+				t.Errorf("AmountDue = %d, want %d", preview.AmountDue, tt.due)
+			}
+		})
+	}
+}
+
+func TestPreviewUpgradeRejects(t *testing.T) {
+	const product = `{"data":{"id":"prod_pro","prices":[{"amount":20,"currency":"USD","billing_cycle":"monthly"}]}}`
+	tests := []struct {
+		name  string
+		items string
+		req   SubscriptionUpgradeRequest
+		err   error
+	}{
+		{name: "currency mismatch", req: SubscriptionUpgradeRequest{NewProductID: "prod_pro", Currency: "EUR"}, err: CurrencyMismatchErr},
+		{name: "multiple items", items: `,"items":[{"id":"a","units":1},{"id":"b","units":2}]`, req: SubscriptionUpgradeRequest{NewProductID: "prod_pro"}, err: UnsupportedSubscriptionItemsErr},
+		{name: "price not found", req: SubscriptionUpgradeRequest{NewProductID: "prod_pro", BillingCycle: "yearly"}, err: PriceNotFoundErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.Contains(r.URL.Path, "/subscriptions/") {
+					fmt.Fprintf(w, `{"data":{"id":"sub_1","product_id":"prod_basic","status":"active","amount":10,"currency":"USD","billing_cycle":"monthly",`+
+						`"current_period_start":"2026-01-01T00:00:00Z","current_period_end":"2026-01-31T00:00:00Z"%s}}`, tt.items)
+					return
+				}
+				fmt.Fprint(w, product)
+			}))
+			defer srv.Close()
+			client, err := NewClient("key", "secret", true, WithProxyUrl(srv.URL))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = client.PreviewUpgrade(context.Background(), "sub_1", &UpgradePreviewRequest{SubscriptionUpgradeRequest: tt.req})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package creem
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cloud-evan/gocreem"
+)
+
+// 跳转URL中的签名参数
+const RedirectSignatureParam = "signature"
+
+// redirectParamOrder 参与签名的参数及顺序
+var redirectParamOrder = []string{
+	"request_id",
+	"checkout_id",
+	"order_id",
+	"customer_id",
+	"subscription_id",
+	"product_id",
+}
+
+// RedirectParams 结账完成后跳转到 SuccessURL 时携带的参数
+type RedirectParams struct {
+	RequestID      string // CheckoutSessionCreateRequest.RequestID
+	CheckoutID     string
+	OrderID        string
+	CustomerID     string
+	SubscriptionID string // 一次性购买时为空
+	ProductID      string
+}
+
+// SignRedirectParams 计算跳转参数签名：按固定顺序拼接非空参数 key=value，以 | 分隔，
+// 末尾追加 salt=密钥，取 SHA-256 十六进制。与 Creem 一致，空值参数（如一次性购买的 subscription_id=）不参与签名
+// 文档：https://docs.creem.io/learn/checkout-session/return-url
+func SignRedirectParams(secret string, query url.Values) string {
+	parts := make([]string, 0, len(redirectParamOrder)+1)
+	for _, key := range redirectParamOrder {
+		if value := query.Get(key); value != "" {
+			parts = append(parts, key+"="+value)
+		}
+	}
+	parts = append(parts, "salt="+secret)
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyRedirect 校验跳转URL的签名并返回参数，签名不符、参数重复或缺少 checkout_id 时返回错误。
+// RotateSecretKey 的过渡期内旧密钥签名的URL同样通过
+func (c *Client) VerifyRedirect(u *url.URL) (*RedirectParams, error) {
+	if u == nil {
+		return nil, gocreem.MissParamErr
+	}
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("[%w]: %v", gocreem.VerifySignatureErr, err)
+	}
+
+	// 同名参数重复时各端取值可能不同，直接拒绝
+	for key, values := range query {
+		if len(values) > 1 {
+			return nil, fmt.Errorf("[%w]: duplicate parameter %s", gocreem.VerifySignatureErr, key)
+		}
+	}
+	signature, err := hex.DecodeString(query.Get(RedirectSignatureParam))
+	if err != nil || len(signature) != sha256.Size {
+		return nil, fmt.Errorf("[%w]: missing or malformed %s", gocreem.VerifySignatureErr, RedirectSignatureParam)
+	}
+
+	cfg := c.config()
+	ok := verifyRedirectSignature(c.currentSecretKey(cfg), query, signature)
+	if !ok && cfg.prevSecretKey != "" && time.Now().Before(cfg.prevSecretUntil) {
+		ok = verifyRedirectSignature(cfg.prevSecretKey, query, signature)
+	}
+	if !ok {
+		return nil, gocreem.VerifySignatureErr
+	}
+
+	params := &RedirectParams{
+		RequestID:      query.Get("request_id"),
+		CheckoutID:     query.Get("checkout_id"),
+		OrderID:        query.Get("order_id"),
+		CustomerID:     query.Get("customer_id"),
+		SubscriptionID: query.Get("subscription_id"),
+		ProductID:      query.Get("product_id"),
+	}
+	if params.CheckoutID == "" {
+		return nil, MissCheckoutSessionIdErr
+	}
+	return params, nil
+}
+
+func verifyRedirectSignature(secret string, query url.Values, signature []byte) bool {
+	expected, _ := hex.DecodeString(SignRedirectParams(secret, query))
+	return subtle.ConstantTimeCompare(expected, signature) == 1
+}
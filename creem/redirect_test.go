@@ -0,0 +1,46 @@
+package creem
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/cloud-evan/gocreem"
+)
+
+// redirectURL 一次性购买的跳转URL，subscription_id 为空值，签名按
+// request_id=req_42|checkout_id=...|order_id=...|customer_id=...|product_id=...|salt=creem_test_secret 计算
+const redirectURL = "https://example.com/success?request_id=req_42&checkout_id=ch_4sUqSUcYt3bnXvGgUeaGpu" +
+	"&order_id=ord_3lFjQXMFbBxEKbDWaQvwQX&customer_id=cust_1OcIK1GEuVvXZwD19tjq2z&subscription_id=" +
+	"&product_id=prod_7GyU1Er6G5p6IhrGSgSU0F&signature=bbcf55d03c318bb4bcbe0176f6cdd08629c2483447d70fd629d02e7f8d6114f4"
+
+func TestVerifyRedirect(t *testing.T) {
+	client, err := NewClient("key", "creem_test_secret", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(redirectURL)
+
+	params, err := client.VerifyRedirect(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.CheckoutID != "ch_4sUqSUcYt3bnXvGgUeaGpu" || params.SubscriptionID != "" || params.RequestID != "req_42" {
+		t.Fatalf("unexpected params %+v", params)
+	}
+
+	// 参数顺序和空值参数不影响签名
+	query := u.Query()
+	query.Del("subscription_id")
+	if got := SignRedirectParams("creem_test_secret", query); got != query.Get(RedirectSignatureParam) {
+		t.Fatalf("expected signature without empty parameter to match, got %s", got)
+	}
+
+	tampered := *u
+	q := u.Query()
+	q.Set("order_id", "ord_other")
+	tampered.RawQuery = q.Encode()
+	if _, err = client.VerifyRedirect(&tampered); !errors.Is(err, gocreem.VerifySignatureErr) {
+		t.Fatalf("expected VerifySignatureErr for tampered URL, got %v", err)
+	}
+}
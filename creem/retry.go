@@ -0,0 +1,100 @@
+package creem
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cloud-evan/gocreem/pkg/xhttp"
+)
+
+const (
+	defaultRetryMinBackoff   = 200 * time.Millisecond
+	defaultRetryMaxBackoff   = 5 * time.Second
+	defaultMaxRateLimitWait  = 30 * time.Second
+	defaultRateLimitFallback = time.Second
+)
+
+// RetryPolicy 重试策略
+// 429、网络错误和 5xx 只对 GET/PUT/DELETE 以及带幂等键的 POST 重试，避免重复创建
+type RetryPolicy struct {
+	MaxRetries       int           // 最大重试次数，0 表示不重试
+	MinBackoff       time.Duration // 首次退避时间，默认200ms，之后指数增长
+	MaxBackoff       time.Duration // 最大退避时间，默认5s
+	MaxRateLimitWait time.Duration // Retry-After 最长等待时间，默认30s，超过则不再重试
+}
+
+// WithRetry 开启失败重试
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		if policy.MinBackoff <= 0 {
+			policy.MinBackoff = defaultRetryMinBackoff
+		}
+		if policy.MaxBackoff <= 0 {
+			policy.MaxBackoff = defaultRetryMaxBackoff
+		}
+		if policy.MaxRateLimitWait <= 0 {
+			policy.MaxRateLimitWait = defaultMaxRateLimitWait
+		}
+		c.pending.retry = policy
+	}
+}
+
+// retryWait 判断是否需要重试，返回等待时间以及是否因限流等待
+func (p RetryPolicy) retryWait(method string, idempotent bool, attempt int, res *http.Response, err error) (wait time.Duration, rateLimited, retry bool) {
+	if attempt >= p.MaxRetries {
+		return 0, false, false
+	}
+	// 没有幂等键的 POST 一律不重试，429 也可能已在服务端部分处理
+	if method == http.MethodPost && !idempotent {
+		return 0, false, false
+	}
+	if res != nil && res.StatusCode == http.StatusTooManyRequests {
+		wait = parseRetryAfter(res.Header.Get("Retry-After"), time.Now())
+		if wait <= 0 {
+			wait = defaultRateLimitFallback
+		}
+		if wait > p.MaxRateLimitWait {
+			return 0, false, false
+		}
+		return wait, true, true
+	}
+	if err != nil {
+		if errors.Is(err, xhttp.ErrResponseTooLarge) {
+			return 0, false, false
+		}
+		return p.backoff(attempt), false, true
+	}
+	if res != nil && res.StatusCode >= http.StatusInternalServerError {
+		return p.backoff(attempt), false, true
+	}
+	return 0, false, false
+}
+
+// backoff 指数退避，带少量随机抖动
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.MinBackoff << attempt
+	if d <= 0 || d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if jitter := int64(d / 4); jitter > 0 {
+		d += time.Duration(rand.Int63n(jitter))
+	}
+	return d
+}
+
+// parseRetryAfter 支持秒数和 HTTP 日期两种格式
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if sec, err := strconv.Atoi(v); err == nil {
+		return time.Duration(sec) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return t.Sub(now)
+	}
+	return 0
+}
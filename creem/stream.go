@@ -0,0 +1,91 @@
+package creem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cloud-evan/gocreem"
+)
+
+// decodeListStream 逐条解码列表响应的 data 数组并回调 fn，其余字段（total_count 等）解码到 rest
+func decodeListStream[T any](r io.Reader, rest any, fn func(item *T) error) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	others := make(map[string]json.RawMessage)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return unmarshalStreamErr(err)
+		}
+		key, _ := tok.(string)
+		if key != "data" {
+			var raw json.RawMessage
+			if err = dec.Decode(&raw); err != nil {
+				return unmarshalStreamErr(err)
+			}
+			others[key] = raw
+			continue
+		}
+
+		if tok, err = dec.Token(); err != nil {
+			return unmarshalStreamErr(err)
+		}
+		if tok == nil {
+			continue
+		}
+		if tok != json.Delim('[') {
+			return unmarshalStreamErr(fmt.Errorf("data: expected array, got %v", tok))
+		}
+		for dec.More() {
+			item := new(T)
+			if err = dec.Decode(item); err != nil {
+				return unmarshalStreamErr(err)
+			}
+			if err = fn(item); err != nil {
+				// 提前结束时保留已解码的 total_count 等字段
+				if errors.Is(err, StopStreamErr) {
+					if restErr := decodeRest(others, rest); restErr != nil {
+						return restErr
+					}
+				}
+				return err
+			}
+		}
+		if err = expectDelim(dec, ']'); err != nil {
+			return err
+		}
+	}
+
+	return decodeRest(others, rest)
+}
+
+// decodeRest 将 data 以外的字段解码到 rest
+func decodeRest(others map[string]json.RawMessage, rest any) error {
+	if rest != nil && len(others) > 0 {
+		bs, _ := json.Marshal(others)
+		if err := json.Unmarshal(bs, rest); err != nil {
+			return unmarshalStreamErr(err)
+		}
+	}
+	return nil
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return unmarshalStreamErr(err)
+	}
+	if tok != delim {
+		return unmarshalStreamErr(fmt.Errorf("expected %v, got %v", delim, tok))
+	}
+	return nil
+}
+
+func unmarshalStreamErr(err error) error {
+	return fmt.Errorf("[%w]: %v", gocreem.UnmarshalErr, err)
+}
@@ -6,33 +6,36 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/cloud-evan/gocreem"
 )
 
 // GetSubscription 获取订阅详情
 // 文档：https://docs.creem.io/api-reference/subscription#get-subscription
-func (c *Client) GetSubscription(ctx context.Context, subscriptionID string) (rsp *SubscriptionDetailResponse, err error) {
+func (c *Client) GetSubscription(ctx context.Context, subscriptionID string, opts ...CallOption) (rsp *SubscriptionDetailResponse, err error) {
 	if subscriptionID == "" {
 		return nil, MissSubscriptionIdErr
 	}
 
 	path := fmt.Sprintf(subscriptionDetail, subscriptionID)
-	res, bs, err := c.doCreemGet(ctx, path)
+	meta, bs, err := c.doCreemGet(ctx, apiOp{Name: "GetSubscription", ResourceID: subscriptionID}, path, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	rsp = &SubscriptionDetailResponse{BaseResponse: BaseResponse{Code: gocreem.Success}}
-	if err = json.Unmarshal(bs, rsp); err != nil {
-		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gocreem.UnmarshalErr, err, string(bs))
-	}
-
-	if res.StatusCode != http.StatusOK {
-		rsp.Code = res.StatusCode
+	rsp = &SubscriptionDetailResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusOK {
+		rsp.Code = meta.StatusCode
 		rsp.Error = string(bs)
 		rsp.ErrorResponse = new(ErrorResponse)
 		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, unmarshalResponseErr(meta, bs, err)
 	}
 
 	return rsp, nil
@@ -40,7 +43,7 @@ func (c *Client) GetSubscription(ctx context.Context, subscriptionID string) (rs
 
 // UpdateSubscription 更新订阅
 // 文档：https://docs.creem.io/api-reference/subscription#update-subscription
-func (c *Client) UpdateSubscription(ctx context.Context, subscriptionID string, req *SubscriptionUpdateRequest) (rsp *SubscriptionUpdateResponse, err error) {
+func (c *Client) UpdateSubscription(ctx context.Context, subscriptionID string, req *SubscriptionUpdateRequest, opts ...CallOption) (rsp *SubscriptionUpdateResponse, err error) {
 	if subscriptionID == "" {
 		return nil, MissSubscriptionIdErr
 	}
@@ -48,30 +51,59 @@ func (c *Client) UpdateSubscription(ctx context.Context, subscriptionID string,
 		return nil, errors.New("request is nil")
 	}
 
+	// 参数校验
+	switch req.UpdateBehavior {
+	case "", UpdateBehaviorProrationChargeImmediately, UpdateBehaviorProrationCharge, UpdateBehaviorProrationNone:
+	default:
+		return nil, fmt.Errorf("[%w]: %s", InvalidUpdateBehaviorErr, req.UpdateBehavior)
+	}
+	for _, item := range req.Items {
+		if item.ID == "" && item.ProductID == "" {
+			return nil, MissSubscriptionItemErr
+		}
+		if item.Units <= 0 {
+			return nil, fmt.Errorf("[%w]: units %d", InvalidQuantityErr, item.Units)
+		}
+	}
+
 	path := fmt.Sprintf(subscriptionUpdate, subscriptionID)
-	res, bs, err := c.doCreemPost(ctx, req, path)
+	meta, bs, err := c.doCreemPost(ctx, apiOp{Name: "UpdateSubscription", ResourceID: subscriptionID}, req, path, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	rsp = &SubscriptionUpdateResponse{BaseResponse: BaseResponse{Code: gocreem.Success}}
-	if err = json.Unmarshal(bs, rsp); err != nil {
-		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gocreem.UnmarshalErr, err, string(bs))
-	}
-
-	if res.StatusCode != http.StatusOK {
-		rsp.Code = res.StatusCode
+	rsp = &SubscriptionUpdateResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusOK {
+		rsp.Code = meta.StatusCode
 		rsp.Error = string(bs)
 		rsp.ErrorResponse = new(ErrorResponse)
 		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, unmarshalResponseErr(meta, bs, err)
 	}
 
 	return rsp, nil
 }
 
+// UpdateSubscriptionSeats 修改订阅项的席位数
+// itemID: 订阅项ID，见 Subscription.Items
+// behavior: UpdateBehaviorProration*，为空时由 Creem 决定
+func (c *Client) UpdateSubscriptionSeats(ctx context.Context, subscriptionID, itemID string, units int, behavior string, opts ...CallOption) (rsp *SubscriptionUpdateResponse, err error) {
+	if itemID == "" {
+		return nil, MissSubscriptionItemErr
+	}
+	return c.UpdateSubscription(ctx, subscriptionID, &SubscriptionUpdateRequest{
+		Items:          []SubscriptionItemUpdate{{ID: itemID, Units: units}},
+		UpdateBehavior: behavior,
+	}, opts...)
+}
+
 // UpgradeSubscription 升级订阅
 // 文档：https://docs.creem.io/api-reference/subscription#upgrade-subscription
-func (c *Client) UpgradeSubscription(ctx context.Context, subscriptionID string, req *SubscriptionUpgradeRequest) (rsp *SubscriptionUpgradeResponse, err error) {
+func (c *Client) UpgradeSubscription(ctx context.Context, subscriptionID string, req *SubscriptionUpgradeRequest, opts ...CallOption) (rsp *SubscriptionUpgradeResponse, err error) {
 	if subscriptionID == "" {
 		return nil, MissSubscriptionIdErr
 	}
@@ -84,51 +116,283 @@ func (c *Client) UpgradeSubscription(ctx context.Context, subscriptionID string,
 		return nil, MissProductIdErr
 	}
 
+	if err = c.checkSubscriptionStatus(ctx, subscriptionID, "upgrade", SubscriptionStatus.CanUpgrade, opts); err != nil {
+		return nil, err
+	}
+
 	path := fmt.Sprintf(subscriptionUpgrade, subscriptionID)
-	res, bs, err := c.doCreemPost(ctx, req, path)
+	meta, bs, err := c.doCreemPost(ctx, apiOp{Name: "UpgradeSubscription", ResourceID: subscriptionID}, req, path, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	rsp = &SubscriptionUpgradeResponse{BaseResponse: BaseResponse{Code: gocreem.Success}}
+	rsp = &SubscriptionUpgradeResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusOK {
+		rsp.Code = meta.StatusCode
+		rsp.Error = string(bs)
+		rsp.ErrorResponse = new(ErrorResponse)
+		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
 	if err = json.Unmarshal(bs, rsp); err != nil {
-		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gocreem.UnmarshalErr, err, string(bs))
+		return nil, unmarshalResponseErr(meta, bs, err)
+	}
+
+	return rsp, nil
+}
+
+// CancelSubscription 立即取消订阅
+// 文档：https://docs.creem.io/api-reference/subscription#cancel-subscription
+func (c *Client) CancelSubscription(ctx context.Context, subscriptionID string, opts ...CallOption) (rsp *SubscriptionCancelResponse, err error) {
+	return c.CancelSubscriptionWith(ctx, subscriptionID, nil, opts...)
+}
+
+// CancelSubscriptionWith 按指定方式取消订阅
+// req 为 nil 时与 CancelSubscription 相同；Mode 为 CancelModeEndOfPeriod 时在当前计费周期结束时取消
+// 文档：https://docs.creem.io/api-reference/subscription#cancel-subscription
+func (c *Client) CancelSubscriptionWith(ctx context.Context, subscriptionID string, req *SubscriptionCancelRequest, opts ...CallOption) (rsp *SubscriptionCancelResponse, err error) {
+	if subscriptionID == "" {
+		return nil, MissSubscriptionIdErr
 	}
 
-	if res.StatusCode != http.StatusOK {
-		rsp.Code = res.StatusCode
+	// 参数校验
+	var body interface{}
+	if req != nil {
+		switch req.Mode {
+		case "", CancelModeImmediate, CancelModeEndOfPeriod:
+		default:
+			return nil, fmt.Errorf("[%w]: %s", InvalidCancelModeErr, req.Mode)
+		}
+		body = req
+	}
+
+	if err = c.checkSubscriptionStatus(ctx, subscriptionID, "cancel", SubscriptionStatus.CanCancel, opts); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf(subscriptionCancel, subscriptionID)
+	meta, bs, err := c.doCreemPost(ctx, apiOp{Name: "CancelSubscription", ResourceID: subscriptionID}, body, path, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &SubscriptionCancelResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusOK {
+		rsp.Code = meta.StatusCode
 		rsp.Error = string(bs)
 		rsp.ErrorResponse = new(ErrorResponse)
 		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, unmarshalResponseErr(meta, bs, err)
 	}
 
 	return rsp, nil
 }
 
-// CancelSubscription 取消订阅
-// 文档：https://docs.creem.io/api-reference/subscription#cancel-subscription
-func (c *Client) CancelSubscription(ctx context.Context, subscriptionID string) (rsp *SubscriptionCancelResponse, err error) {
+// CreateSubscription 创建订阅
+// 文档：https://docs.creem.io/api-reference/subscription#create-subscription
+func (c *Client) CreateSubscription(ctx context.Context, req *SubscriptionCreateRequest, opts ...CallOption) (rsp *SubscriptionCreateResponse, err error) {
+	if req == nil {
+		return nil, errors.New("request is nil")
+	}
+
+	// 参数校验
+	if req.CustomerID == "" {
+		return nil, MissCustomerIdErr
+	}
+	if req.ProductID == "" {
+		return nil, MissProductIdErr
+	}
+
+	meta, bs, err := c.doCreemPost(ctx, apiOp{Name: "CreateSubscription"}, req, subscriptionCreate, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &SubscriptionCreateResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusCreated {
+		rsp.Code = meta.StatusCode
+		rsp.Error = string(bs)
+		rsp.ErrorResponse = new(ErrorResponse)
+		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, unmarshalResponseErr(meta, bs, err)
+	}
+
+	return rsp, nil
+}
+
+// ListSubscriptions 获取订阅列表，可按客户、产品、状态过滤
+// 文档：https://docs.creem.io/api-reference/subscription#list-subscriptions
+func (c *Client) ListSubscriptions(ctx context.Context, params *ListParams, opts ...CallOption) (rsp *SubscriptionsListResponse, err error) {
+	if params == nil {
+		params = &ListParams{}
+	}
+
+	// 构建查询参数
+	queryParams := url.Values{}
+	if params.Page > 0 {
+		queryParams.Set("page", strconv.Itoa(params.Page))
+	}
+	if params.Limit > 0 {
+		queryParams.Set("limit", strconv.Itoa(params.Limit))
+	}
+	if params.Status != "" {
+		queryParams.Set("status", params.Status)
+	}
+	if params.CustomerID != "" {
+		queryParams.Set("customer_id", params.CustomerID)
+	}
+	if params.ProductID != "" {
+		queryParams.Set("product_id", params.ProductID)
+	}
+
+	path := subscriptionsList
+	if len(queryParams) > 0 {
+		path += "?" + queryParams.Encode()
+	}
+
+	meta, bs, err := c.doCreemGet(ctx, apiOp{Name: "ListSubscriptions"}, path, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &SubscriptionsListResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusOK {
+		rsp.Code = meta.StatusCode
+		rsp.Error = string(bs)
+		rsp.ErrorResponse = new(ErrorResponse)
+		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, unmarshalResponseErr(meta, bs, err)
+	}
+
+	return rsp, nil
+}
+
+// PauseSubscription 暂停订阅，暂停期间不再扣费
+// 文档：https://docs.creem.io/api-reference/subscription#pause-subscription
+func (c *Client) PauseSubscription(ctx context.Context, subscriptionID string, opts ...CallOption) (rsp *SubscriptionPauseResponse, err error) {
 	if subscriptionID == "" {
 		return nil, MissSubscriptionIdErr
 	}
 
-	path := fmt.Sprintf(subscriptionCancel, subscriptionID)
-	res, bs, err := c.doCreemPost(ctx, nil, path)
+	if err = c.checkSubscriptionStatus(ctx, subscriptionID, "pause", SubscriptionStatus.CanPause, opts); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf(subscriptionPause, subscriptionID)
+	meta, bs, err := c.doCreemPost(ctx, apiOp{Name: "PauseSubscription", ResourceID: subscriptionID}, nil, path, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &SubscriptionPauseResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusOK {
+		rsp.Code = meta.StatusCode
+		rsp.Error = string(bs)
+		rsp.ErrorResponse = new(ErrorResponse)
+		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, unmarshalResponseErr(meta, bs, err)
+	}
+
+	return rsp, nil
+}
+
+// ResumeSubscription 恢复已暂停的订阅
+// 文档：https://docs.creem.io/api-reference/subscription#resume-subscription
+func (c *Client) ResumeSubscription(ctx context.Context, subscriptionID string, opts ...CallOption) (rsp *SubscriptionResumeResponse, err error) {
+	if subscriptionID == "" {
+		return nil, MissSubscriptionIdErr
+	}
+
+	if err = c.checkSubscriptionStatus(ctx, subscriptionID, "resume", SubscriptionStatus.CanResume, opts); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf(subscriptionResume, subscriptionID)
+	meta, bs, err := c.doCreemPost(ctx, apiOp{Name: "ResumeSubscription", ResourceID: subscriptionID}, nil, path, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	rsp = &SubscriptionCancelResponse{BaseResponse: BaseResponse{Code: gocreem.Success}}
+	rsp = &SubscriptionResumeResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusOK {
+		rsp.Code = meta.StatusCode
+		rsp.Error = string(bs)
+		rsp.ErrorResponse = new(ErrorResponse)
+		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
 	if err = json.Unmarshal(bs, rsp); err != nil {
-		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gocreem.UnmarshalErr, err, string(bs))
+		return nil, unmarshalResponseErr(meta, bs, err)
 	}
 
-	if res.StatusCode != http.StatusOK {
-		rsp.Code = res.StatusCode
+	return rsp, nil
+}
+
+// RecordUsage 上报按用量计费订阅的用量，建议配合 WithIdempotencyKey 使用，批量上报见 UsageRecorder
+func (c *Client) RecordUsage(ctx context.Context, subscriptionID string, req *UsageRecordRequest, opts ...CallOption) (rsp *UsageRecordResponse, err error) {
+	if subscriptionID == "" {
+		return nil, MissSubscriptionIdErr
+	}
+	if req == nil {
+		return nil, errors.New("request is nil")
+	}
+
+	// 参数校验
+	for _, record := range req.Records {
+		if record.Quantity <= 0 {
+			return nil, fmt.Errorf("[%w]: quantity %d", InvalidQuantityErr, record.Quantity)
+		}
+	}
+
+	path := fmt.Sprintf(subscriptionUsage, subscriptionID)
+	meta, bs, err := c.doCreemPost(ctx, apiOp{Name: "RecordUsage", ResourceID: subscriptionID}, req, path, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &UsageRecordResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusOK {
+		rsp.Code = meta.StatusCode
 		rsp.Error = string(bs)
 		rsp.ErrorResponse = new(ErrorResponse)
 		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, unmarshalResponseErr(meta, bs, err)
 	}
 
 	return rsp, nil
 }
+
+// checkSubscriptionStatus 查询订阅当前状态，状态不允许 action 时提前返回 SubscriptionStatusErr。
+// 每次检查额外发出一次 GET 请求；查询失败或状态未知时不拦截，由实际请求返回 Creem 的错误
+func (c *Client) checkSubscriptionStatus(ctx context.Context, subscriptionID, action string, allowed func(SubscriptionStatus) bool, opts []CallOption) error {
+	rsp, err := c.GetSubscription(ctx, subscriptionID, readOptions(opts)...)
+	if err != nil || rsp.Code != gocreem.Success || !rsp.Data.Status.Valid() {
+		return nil
+	}
+	if !allowed(rsp.Data.Status) {
+		return fmt.Errorf("[%w]: cannot %s subscription %s in status %s", SubscriptionStatusErr, action, subscriptionID, rsp.Data.Status)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package creem
+
+// SubscriptionStatus 订阅状态
+type SubscriptionStatus string
+
+// 订阅状态
+const (
+	SubscriptionActive          SubscriptionStatus = "active"
+	SubscriptionCanceled        SubscriptionStatus = "canceled"
+	SubscriptionPaused          SubscriptionStatus = "paused"
+	SubscriptionPastDue         SubscriptionStatus = "past_due"
+	SubscriptionUnpaid          SubscriptionStatus = "unpaid"
+	SubscriptionTrialing        SubscriptionStatus = "trialing"
+	SubscriptionScheduledCancel SubscriptionStatus = "scheduled_cancel" // 已设置周期结束时取消
+)
+
+// 旧版无类型订阅状态常量，可直接与 SubscriptionStatus 比较
+//
+// Deprecated: 使用 SubscriptionActive 等 SubscriptionStatus 类型常量
+const (
+	SubscriptionStatusActive   = "active"
+	SubscriptionStatusCanceled = "canceled"
+	SubscriptionStatusPaused   = "paused"
+	SubscriptionStatusPastDue  = "past_due"
+	SubscriptionStatusUnpaid   = "unpaid"
+	SubscriptionStatusTrialing = "trialing"
+)
+
+// subscriptionTransitions 订阅状态流转表，canceled 为终态
+var subscriptionTransitions = map[SubscriptionStatus][]SubscriptionStatus{
+	SubscriptionTrialing: {
+		SubscriptionActive,
+		SubscriptionPastDue,
+		SubscriptionUnpaid,
+		SubscriptionPaused,
+		SubscriptionScheduledCancel,
+		SubscriptionCanceled,
+	},
+	SubscriptionActive: {
+		SubscriptionPastDue,
+		SubscriptionUnpaid,
+		SubscriptionPaused,
+		SubscriptionScheduledCancel,
+		SubscriptionCanceled,
+	},
+	SubscriptionPastDue: {
+		SubscriptionActive,
+		SubscriptionUnpaid,
+		SubscriptionCanceled,
+	},
+	SubscriptionUnpaid: {
+		SubscriptionActive,
+		SubscriptionCanceled,
+	},
+	SubscriptionPaused: {
+		SubscriptionActive,
+		SubscriptionCanceled,
+	},
+	SubscriptionScheduledCancel: {
+		SubscriptionActive,
+		SubscriptionCanceled,
+	},
+	SubscriptionCanceled: nil,
+}
+
+// Valid 是否为已知状态
+func (s SubscriptionStatus) Valid() bool {
+	_, ok := subscriptionTransitions[s]
+	return ok
+}
+
+// IsTerminal 是否为终态，终态订阅不能再做任何操作
+func (s SubscriptionStatus) IsTerminal() bool {
+	return s == SubscriptionCanceled
+}
+
+// CanTransitionTo 是否允许从当前状态流转到 next
+func (s SubscriptionStatus) CanTransitionTo(next SubscriptionStatus) bool {
+	for _, to := range subscriptionTransitions[s] {
+		if to == next {
+			return true
+		}
+	}
+	return false
+}
+
+// CanUpgrade 是否允许升级或更换产品
+func (s SubscriptionStatus) CanUpgrade() bool {
+	return s == SubscriptionActive || s == SubscriptionTrialing
+}
+
+// CanPause 是否允许暂停
+func (s SubscriptionStatus) CanPause() bool {
+	return s.CanTransitionTo(SubscriptionPaused)
+}
+
+// CanResume 是否允许恢复
+func (s SubscriptionStatus) CanResume() bool {
+	return s == SubscriptionPaused
+}
+
+// CanCancel 是否允许取消
+func (s SubscriptionStatus) CanCancel() bool {
+	return s.CanTransitionTo(SubscriptionCanceled)
+}
+
+// IsEntitled 当前状态下客户是否可以使用订阅的权益。
+// past_due 和 unpaid 等待付款，paused 暂停扣费，均不可使用；scheduled_cancel 在周期结束前仍可使用
+func (s SubscriptionStatus) IsEntitled() bool {
+	switch s {
+	case SubscriptionActive, SubscriptionTrialing, SubscriptionScheduledCancel:
+		return true
+	}
+	return false
+}
+
+func (s SubscriptionStatus) String() string {
+	return string(s)
+}
@@ -3,7 +3,8 @@ package creem
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -13,7 +14,63 @@ import (
 
 // ListTransactions 获取交易列表
 // 文档：https://docs.creem.io/api-reference/transactions#list-transactions
-func (c *Client) ListTransactions(ctx context.Context, params *ListParams) (rsp *TransactionsListResponse, err error) {
+func (c *Client) ListTransactions(ctx context.Context, params *ListParams, opts ...CallOption) (rsp *TransactionsListResponse, err error) {
+	path := transactionsListPath(params)
+	meta, bs, err := c.doCreemGet(ctx, apiOp{Name: "ListTransactions"}, path, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp = &TransactionsListResponse{BaseResponse: BaseResponse{Code: gocreem.Success, Meta: meta}}
+	if meta.StatusCode != http.StatusOK {
+		rsp.Code = meta.StatusCode
+		rsp.Error = string(bs)
+		rsp.ErrorResponse = new(ErrorResponse)
+		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+		return rsp, nil
+	}
+
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, unmarshalResponseErr(meta, bs, err)
+	}
+
+	return rsp, nil
+}
+
+// ListTransactionsStream 流式获取交易列表，逐条解码并回调 fn，不在内存中缓存整页响应，
+// 适合单页数据超过 SetBodySize 限制的场景。返回的 rsp.Data 为空，TotalCount 等分页字段正常填充。
+// fn 返回 StopStreamErr 时提前结束，返回 data 之前已解码的 TotalCount 等字段；返回其他错误时停止并原样返回
+func (c *Client) ListTransactionsStream(ctx context.Context, params *ListParams, fn func(transaction *Transaction) error, opts ...CallOption) (rsp *TransactionsListResponse, err error) {
+	if fn == nil {
+		return nil, gocreem.MissParamErr
+	}
+
+	rsp = &TransactionsListResponse{BaseResponse: BaseResponse{Code: gocreem.Success}}
+	path := transactionsListPath(params)
+	meta, bs, err := c.doCreemGetStream(ctx, apiOp{Name: "ListTransactions"}, path, func(body io.Reader) error {
+		return decodeListStream(body, rsp, fn)
+	}, opts...)
+	if err != nil && !errors.Is(err, StopStreamErr) {
+		// 响应体解码失败时保留元信息，fn 返回的错误原样返回
+		if meta != nil && errors.Is(err, gocreem.UnmarshalErr) {
+			return nil, &ResponseError{Meta: meta, Err: err}
+		}
+		return nil, err
+	}
+	rsp.Meta = meta
+
+	if meta != nil && meta.StatusCode != http.StatusOK {
+		rsp.Code = meta.StatusCode
+		rsp.Error = string(bs)
+		rsp.ErrorResponse = new(ErrorResponse)
+		_ = json.Unmarshal(bs, rsp.ErrorResponse)
+	}
+
+	return rsp, nil
+}
+
+// transactionsListPath 构建交易列表请求路径
+func transactionsListPath(params *ListParams) string {
 	if params == nil {
 		params = &ListParams{}
 	}
@@ -47,22 +104,5 @@ func (c *Client) ListTransactions(ctx context.Context, params *ListParams) (rsp
 		path += "?" + queryParams.Encode()
 	}
 
-	res, bs, err := c.doCreemGet(ctx, path)
-	if err != nil {
-		return nil, err
-	}
-
-	rsp = &TransactionsListResponse{BaseResponse: BaseResponse{Code: gocreem.Success}}
-	if err = json.Unmarshal(bs, rsp); err != nil {
-		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gocreem.UnmarshalErr, err, string(bs))
-	}
-
-	if res.StatusCode != http.StatusOK {
-		rsp.Code = res.StatusCode
-		rsp.Error = string(bs)
-		rsp.ErrorResponse = new(ErrorResponse)
-		_ = json.Unmarshal(bs, rsp.ErrorResponse)
-	}
-
-	return rsp, nil
+	return path
 }
@@ -0,0 +1,65 @@
+package creem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListTransactionsStreamStop(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		total int
+	}{
+		{name: "total before data", body: `{"total_count":42,"page":1,"data":[{"id":"tx_1"},{"id":"tx_2"},{"id":"tx_3"}]}`, total: 42},
+		{name: "total after data", body: `{"data":[{"id":"tx_1"},{"id":"tx_2"},{"id":"tx_3"}],"total_count":42}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, tt.body)
+			}))
+			defer srv.Close()
+			client, err := NewClient("key", "secret", true, WithProxyUrl(srv.URL))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var seen []string
+			rsp, err := client.ListTransactionsStream(context.Background(), nil, func(tx *Transaction) error {
+				seen = append(seen, tx.ID)
+				if len(seen) == 2 {
+					return StopStreamErr
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(seen) != 2 || rsp.TotalCount != tt.total {
+				t.Fatalf("expected 2 items and total %d, got %v and %d", tt.total, seen, rsp.TotalCount)
+			}
+		})
+	}
+}
+
+func TestListTransactionsStreamCallbackError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"total_count":1,"data":[{"id":"tx_1"}]}`)
+	}))
+	defer srv.Close()
+	client, err := NewClient("key", "secret", true, WithProxyUrl(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failed := errors.New("callback failed")
+	_, err = client.ListTransactionsStream(context.Background(), nil, func(*Transaction) error { return failed })
+	if !errors.Is(err, failed) {
+		t.Fatalf("expected callback error, got %v", err)
+	}
+}
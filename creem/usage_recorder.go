@@ -0,0 +1,375 @@
+package creem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloud-evan/gocreem"
+)
+
+const (
+	defaultUsageWindow        = time.Hour
+	defaultUsageFlushInterval = 30 * time.Second
+	defaultUsageBatchSize     = 100
+	defaultUsageMaxPending    = 10000
+)
+
+// UsageBatch 一次上报的用量，失败后以同一个幂等键重发，保证至少送达一次且重发不会重复计费
+type UsageBatch struct {
+	SubscriptionID string
+	IdempotencyKey string
+	Records        []UsageRecord
+	Attempts       int
+}
+
+// usageKey 按订阅和统计周期聚合
+type usageKey struct {
+	subscriptionID string
+	periodStart    time.Time
+}
+
+// UsageRecorder 在进程内缓冲用量事件，按订阅和统计周期聚合，周期结束后批量上报，每个订阅的每个周期通常只上报一次。
+// 周期结束后才记录的用量（RecordAt 传入过去的时间）会并入尚未发送的批次，该周期已发送时作为增量另起一批上报。
+// 上报失败的批次保留在队列中，下次 Flush 时按原幂等键重发；进程退出前请调用 Close，未送达的用量会丢失。
+// 缓冲、待发送和未取走的丢弃记录总数达到上限后，新周期的用量返回 UsageQueueFullErr
+type UsageRecorder struct {
+	client     *Client
+	window     time.Duration
+	interval   time.Duration
+	batchSize  int
+	maxPending int
+	onError    func(*UsageBatch, error)
+	now        func() time.Time
+
+	mu      sync.Mutex
+	buckets map[usageKey]int64
+	pending []*UsageBatch
+	dropped []*UsageBatch // 后台上报被拒绝且未设置 onError 的批次，由下次 Flush 返回
+	queued  int           // pending 和 dropped 中的记录数
+
+	flushMu sync.Mutex
+
+	loopMu sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+type UsageRecorderOption func(*UsageRecorder)
+
+// NewUsageRecorder 初始化用量上报器
+// 默认按1小时聚合、每30秒上报已结束的周期，每批最多100条记录，最多积压10000条记录
+func NewUsageRecorder(client *Client, options ...UsageRecorderOption) (*UsageRecorder, error) {
+	if client == nil {
+		return nil, MissCreemInitParamErr
+	}
+
+	r := &UsageRecorder{
+		client:     client,
+		window:     defaultUsageWindow,
+		interval:   defaultUsageFlushInterval,
+		batchSize:  defaultUsageBatchSize,
+		maxPending: defaultUsageMaxPending,
+		now:        time.Now,
+		buckets:    make(map[usageKey]int64),
+	}
+	for _, option := range options {
+		option(r)
+	}
+	return r, nil
+}
+
+// WithUsageWindow 设置聚合的统计周期，同一订阅同一周期内的用量合并为一条记录
+func WithUsageWindow(d time.Duration) UsageRecorderOption {
+	return func(r *UsageRecorder) {
+		if d > 0 {
+			r.window = d
+		}
+	}
+}
+
+// WithUsageFlushInterval 设置后台上报间隔，每次上报已结束的周期
+func WithUsageFlushInterval(d time.Duration) UsageRecorderOption {
+	return func(r *UsageRecorder) {
+		if d > 0 {
+			r.interval = d
+		}
+	}
+}
+
+// WithUsageBatchSize 设置每批最多包含的记录数
+func WithUsageBatchSize(n int) UsageRecorderOption {
+	return func(r *UsageRecorder) {
+		if n > 0 {
+			r.batchSize = n
+		}
+	}
+}
+
+// WithUsageMaxPending 设置积压记录数上限，包括缓冲中、待发送和未取走的丢弃记录
+func WithUsageMaxPending(n int) UsageRecorderOption {
+	return func(r *UsageRecorder) {
+		if n > 0 {
+			r.maxPending = n
+		}
+	}
+}
+
+// WithUsageErrorHandler 批次上报失败时回调，可用于告警或落盘
+func WithUsageErrorHandler(fn func(batch *UsageBatch, err error)) UsageRecorderOption {
+	return func(r *UsageRecorder) {
+		r.onError = fn
+	}
+}
+
+// Record 记录当前时间的用量
+func (r *UsageRecorder) Record(subscriptionID string, quantity int64) error {
+	return r.RecordAt(subscriptionID, quantity, r.now())
+}
+
+// RecordAt 记录指定时间的用量，计入该时间所在的统计周期。
+// 积压达到上限时新周期的用量返回 UsageQueueFullErr 且不计入，已有周期的用量仍会合并
+func (r *UsageRecorder) RecordAt(subscriptionID string, quantity int64, at time.Time) error {
+	if subscriptionID == "" {
+		return MissSubscriptionIdErr
+	}
+	if quantity <= 0 {
+		return fmt.Errorf("[%w]: quantity %d", InvalidQuantityErr, quantity)
+	}
+
+	key := usageKey{subscriptionID: subscriptionID, periodStart: at.UTC().Truncate(r.window)}
+	r.mu.Lock()
+	if _, ok := r.buckets[key]; !ok && len(r.buckets)+r.queued >= r.maxPending {
+		r.mu.Unlock()
+		return fmt.Errorf("[%w]: %d records pending", UsageQueueFullErr, r.maxPending)
+	}
+	r.buckets[key] += quantity
+	r.mu.Unlock()
+	return nil
+}
+
+// Pending 尚未送达的批次数，不含缓冲中的用量
+func (r *UsageRecorder) Pending() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.pending)
+}
+
+// Flush 将已结束周期的用量打包并上报，同时重发此前失败的批次，返回本次失败的错误。
+// 当前周期仍在累计，不会上报，见 Close。
+// dropped 为被 Creem 明确拒绝、不会再重发的批次，包括后台上报时丢弃且未交给 onError 的批次
+func (r *UsageRecorder) Flush(ctx context.Context) (dropped []*UsageBatch, err error) {
+	return r.takeDropped(r.flush(ctx, false))
+}
+
+// takeDropped 在本次丢弃的批次前加上后台上报时留下的批次
+func (r *UsageRecorder) takeDropped(dropped []*UsageBatch, err error) ([]*UsageBatch, error) {
+	r.mu.Lock()
+	if len(r.dropped) > 0 {
+		for _, batch := range r.dropped {
+			r.queued -= len(batch.Records)
+		}
+		dropped = append(r.dropped, dropped...)
+		r.dropped = nil
+	}
+	r.mu.Unlock()
+	return dropped, err
+}
+
+// flush 上报全部批次，返回本次丢弃的批次，all 为 true 时包括未结束的周期
+func (r *UsageRecorder) flush(ctx context.Context, all bool) (dropped []*UsageBatch, err error) {
+	r.flushMu.Lock()
+	defer r.flushMu.Unlock()
+
+	r.seal(all)
+	r.mu.Lock()
+	batches := append([]*UsageBatch(nil), r.pending...)
+	r.mu.Unlock()
+
+	var errs []error
+	for _, batch := range batches {
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+			break
+		}
+		drop, err := r.send(ctx, batch)
+		if err != nil {
+			errs = append(errs, err)
+			if r.onError != nil {
+				r.onError(batch, err)
+			}
+		}
+		if err == nil || drop {
+			r.remove(batch)
+		}
+		if drop {
+			dropped = append(dropped, batch)
+		}
+	}
+	return dropped, errors.Join(errs...)
+}
+
+// seal 将已结束周期的缓冲按订阅拆分为批次放入待发送队列，all 为 true 时包括未结束的周期。
+// 只在 flushMu 下调用，此时没有批次正在发送
+func (r *UsageRecorder) seal(all bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.buckets) == 0 {
+		return
+	}
+
+	now := r.now()
+	records := make(map[string][]UsageRecord)
+	for key, quantity := range r.buckets {
+		record := UsageRecord{
+			PeriodStart: key.periodStart,
+			PeriodEnd:   key.periodStart.Add(r.window),
+			Quantity:    quantity,
+		}
+		if !all && record.PeriodEnd.After(now) {
+			continue
+		}
+		delete(r.buckets, key)
+		if r.mergeUnsent(key.subscriptionID, record) {
+			continue
+		}
+		records[key.subscriptionID] = append(records[key.subscriptionID], record)
+		r.queued++
+	}
+
+	ids := make([]string, 0, len(records))
+	for id := range records {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		list := records[id]
+		sort.Slice(list, func(i, j int) bool { return list[i].PeriodStart.Before(list[j].PeriodStart) })
+		for len(list) > 0 {
+			n := min(len(list), r.batchSize)
+			r.pending = append(r.pending, &UsageBatch{
+				SubscriptionID: id,
+				IdempotencyKey: newIdempotencyKey(),
+				Records:        list[:n:n],
+			})
+			list = list[n:]
+		}
+	}
+}
+
+// mergeUnsent 将迟到的用量并入同一订阅尚未发送过的批次，已发送的批次内容不能再改，否则同一幂等键对应不同的请求
+func (r *UsageRecorder) mergeUnsent(subscriptionID string, record UsageRecord) bool {
+	var room *UsageBatch
+	for _, batch := range r.pending {
+		if batch.Attempts > 0 || batch.SubscriptionID != subscriptionID {
+			continue
+		}
+		for i := range batch.Records {
+			if batch.Records[i].PeriodStart.Equal(record.PeriodStart) {
+				batch.Records[i].Quantity += record.Quantity
+				return true
+			}
+		}
+		if room == nil && len(batch.Records) < r.batchSize {
+			room = batch
+		}
+	}
+	if room == nil {
+		return false
+	}
+	room.Records = append(room.Records, record)
+	sort.Slice(room.Records, func(i, j int) bool { return room.Records[i].PeriodStart.Before(room.Records[j].PeriodStart) })
+	r.queued++
+	return true
+}
+
+// send 上报一个批次，drop 为 true 表示 Creem 明确拒绝，重发也不会成功
+func (r *UsageRecorder) send(ctx context.Context, batch *UsageBatch) (drop bool, err error) {
+	r.mu.Lock()
+	batch.Attempts++
+	r.mu.Unlock()
+
+	rsp, err := r.client.RecordUsage(ctx, batch.SubscriptionID, &UsageRecordRequest{Records: batch.Records}, WithIdempotencyKey(batch.IdempotencyKey))
+	if err != nil {
+		return false, err
+	}
+	if rsp.Code == gocreem.Success {
+		return false, nil
+	}
+	err = fmt.Errorf("record usage %s: status %d: %s", batch.SubscriptionID, rsp.Code, rsp.Error)
+	switch {
+	case rsp.Code == http.StatusTooManyRequests || rsp.Code == http.StatusRequestTimeout || rsp.Code >= http.StatusInternalServerError:
+		return false, err
+	default:
+		return true, err
+	}
+}
+
+func (r *UsageRecorder) remove(batch *UsageBatch) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, b := range r.pending {
+		if b == batch {
+			r.pending = append(r.pending[:i], r.pending[i+1:]...)
+			r.queued -= len(batch.Records)
+			return
+		}
+	}
+}
+
+// Start 启动后台定时上报，重复调用无效
+func (r *UsageRecorder) Start(ctx context.Context) {
+	r.loopMu.Lock()
+	defer r.loopMu.Unlock()
+	if r.cancel != nil {
+		return
+	}
+	ctx, r.cancel = context.WithCancel(ctx)
+	r.done = make(chan struct{})
+	go r.loop(ctx, r.done)
+}
+
+// Stop 停止后台上报并等待其退出，不会上报剩余用量，见 Close
+func (r *UsageRecorder) Stop() {
+	r.loopMu.Lock()
+	cancel, done := r.cancel, r.done
+	r.cancel, r.done = nil, nil
+	r.loopMu.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// Close 停止后台上报并上报剩余用量，包括未结束的周期，返回值同 Flush
+func (r *UsageRecorder) Close(ctx context.Context) ([]*UsageBatch, error) {
+	r.Stop()
+	return r.takeDropped(r.flush(ctx, true))
+}
+
+func (r *UsageRecorder) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		// 失败的批次留在队列中，错误已交给 onError；未设置 onError 时丢弃的批次留给下次 Flush 返回
+		dropped, _ := r.flush(ctx, false)
+		if len(dropped) > 0 && r.onError == nil {
+			r.mu.Lock()
+			for _, batch := range dropped {
+				r.queued += len(batch.Records)
+			}
+			r.dropped = append(r.dropped, dropped...)
+			r.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package creem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// usageRequest 测试服务端收到的一次上报
+type usageRequest struct {
+	SubscriptionID string
+	IdempotencyKey string
+	Records        []UsageRecord
+}
+
+// usageServer 按订阅ID依次返回 statuses 中的状态码，用完后返回 200
+type usageServer struct {
+	mu       sync.Mutex
+	statuses map[string][]int
+	requests []usageRequest
+}
+
+func (s *usageServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// /v1/subscriptions/{id}/usage
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/subscriptions/"), "/usage")
+	req := new(UsageRecordRequest)
+	_ = json.NewDecoder(r.Body).Decode(req)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, usageRequest{SubscriptionID: id, IdempotencyKey: r.Header.Get(HeaderIdempotencyKey), Records: req.Records})
+	status := http.StatusOK
+	if list := s.statuses[id]; len(list) > 0 {
+		status, s.statuses[id] = list[0], list[1:]
+	}
+	s.mu.Unlock()
+
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(`{}`))
+}
+
+func (s *usageServer) sent() []usageRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]usageRequest(nil), s.requests...)
+}
+
+// testUsageRecorder 返回时钟固定在 now 的上报器，时钟可通过返回的函数推进
+func testUsageRecorder(t *testing.T, server *usageServer, options ...UsageRecorderOption) (*UsageRecorder, func(time.Duration)) {
+	t.Helper()
+	srv := httptest.NewServer(server)
+	t.Cleanup(srv.Close)
+	client, err := NewClient("key", "secret", true, WithProxyUrl(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewUsageRecorder(client, options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 1, 1, 10, 15, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+	return r, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestUsageRecorderQueueFull(t *testing.T) {
+	r, _ := testUsageRecorder(t, &usageServer{}, WithUsageMaxPending(2))
+
+	if err := r.Record("sub_a", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Record("sub_b", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Record("sub_c", 1); !errors.Is(err, UsageQueueFullErr) {
+		t.Fatalf("expected UsageQueueFullErr, got %v", err)
+	}
+	// 已有周期的用量不占用新的位置
+	if err := r.Record("sub_a", 5); err != nil {
+		t.Fatalf("expected existing period to accept usage, got %v", err)
+	}
+}
+
+func TestUsageRecorderFlushOnlyClosedPeriods(t *testing.T) {
+	server := &usageServer{}
+	r, advance := testUsageRecorder(t, server)
+	ctx := context.Background()
+
+	_ = r.Record("sub_a", 2)
+	if _, err := r.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(server.sent()); n != 0 {
+		t.Fatalf("expected open period to be held back, got %d requests", n)
+	}
+
+	_ = r.Record("sub_a", 3)
+	advance(time.Hour)
+	if _, err := r.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	sent := server.sent()
+	if len(sent) != 1 || len(sent[0].Records) != 1 || sent[0].Records[0].Quantity != 5 {
+		t.Fatalf("expected one record with quantity 5, got %+v", sent)
+	}
+}
+
+func TestUsageRecorderRetryKeepsIdempotencyKey(t *testing.T) {
+	server := &usageServer{statuses: map[string][]int{"sub_a": {http.StatusServiceUnavailable, http.StatusTooManyRequests}}}
+	var failures int
+	r, advance := testUsageRecorder(t, server, WithUsageErrorHandler(func(*UsageBatch, error) { failures++ }))
+	ctx := context.Background()
+
+	_ = r.Record("sub_a", 1)
+	advance(time.Hour)
+	for i := 0; i < 3; i++ {
+		dropped, _ := r.Flush(ctx)
+		if len(dropped) != 0 {
+			t.Fatalf("expected retryable errors to keep the batch, dropped %d", len(dropped))
+		}
+	}
+	if r.Pending() != 0 {
+		t.Fatalf("expected batch to be delivered, %d pending", r.Pending())
+	}
+
+	sent := server.sent()
+	if len(sent) != 3 || failures != 2 {
+		t.Fatalf("expected 3 attempts and 2 failures, got %d and %d", len(sent), failures)
+	}
+	for _, req := range sent[1:] {
+		if req.IdempotencyKey == "" || req.IdempotencyKey != sent[0].IdempotencyKey {
+			t.Fatalf("expected retries to reuse key %q, got %q", sent[0].IdempotencyKey, req.IdempotencyKey)
+		}
+	}
+}
+
+func TestUsageRecorderDropsRejectedBatch(t *testing.T) {
+	server := &usageServer{statuses: map[string][]int{"sub_bad": {http.StatusUnprocessableEntity}}}
+	r, advance := testUsageRecorder(t, server, WithUsageMaxPending(2))
+	ctx := context.Background()
+
+	_ = r.Record("sub_bad", 1)
+	_ = r.Record("sub_ok", 1)
+	advance(time.Hour)
+	dropped, err := r.Flush(ctx)
+	if err == nil {
+		t.Fatal("expected error for rejected batch")
+	}
+	if len(dropped) != 1 || dropped[0].SubscriptionID != "sub_bad" {
+		t.Fatalf("expected sub_bad to be dropped, got %+v", dropped)
+	}
+	if r.Pending() != 0 {
+		t.Fatalf("expected no pending batches, got %d", r.Pending())
+	}
+	// 丢弃和送达的记录都不再占用积压
+	if err = r.Record("sub_c", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err = r.Record("sub_d", 1); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUsageRecorderMergesLateUsageIntoUnsentBatch(t *testing.T) {
+	server := &usageServer{}
+	r, advance := testUsageRecorder(t, server)
+
+	start := r.now()
+	_ = r.Record("sub_a", 1)
+	advance(time.Hour)
+	// 取消的 ctx 只封装批次，不发送
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _ = r.Flush(canceled)
+	if r.Pending() != 1 {
+		t.Fatalf("expected one unsent batch, got %d", r.Pending())
+	}
+
+	_ = r.RecordAt("sub_a", 4, start)
+	if _, err := r.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	sent := server.sent()
+	if len(sent) != 1 || len(sent[0].Records) != 1 || sent[0].Records[0].Quantity != 5 {
+		t.Fatalf("expected late usage merged into one record, got %+v", sent)
+	}
+}
+
+func TestUsageRecorderClose(t *testing.T) {
+	server := &usageServer{statuses: map[string][]int{"sub_bad": {http.StatusBadRequest}}}
+	r, _ := testUsageRecorder(t, server)
+
+	_ = r.Record("sub_a", 7)
+	_ = r.Record("sub_bad", 1)
+	dropped, err := r.Close(context.Background())
+	if err == nil || len(dropped) != 1 || dropped[0].SubscriptionID != "sub_bad" {
+		t.Fatalf("expected sub_bad to be dropped, got %+v, %v", dropped, err)
+	}
+
+	var delivered bool
+	for _, req := range server.sent() {
+		if req.SubscriptionID == "sub_a" && len(req.Records) == 1 && req.Records[0].Quantity == 7 {
+			delivered = true
+		}
+	}
+	if !delivered {
+		t.Fatalf("expected Close to report the open period, got %+v", server.sent())
+	}
+}
@@ -0,0 +1,92 @@
+package creem
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/cloud-evan/gocreem"
+)
+
+// webhookBodyLimit Webhook请求体大小上限
+const webhookBodyLimit = 1 << 20
+
+// SignWebhookPayload 使用密钥对Webhook请求体签名，返回十六进制HMAC-SHA256
+func SignWebhookPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookPayload 校验Webhook签名
+func VerifyWebhookPayload(secret string, payload []byte, signature string) error {
+	if signature == "" {
+		return fmt.Errorf("[%w]: missing %s header", gocreem.VerifySignatureErr, HeaderSignature)
+	}
+	expected, err := hex.DecodeString(SignWebhookPayload(secret, payload))
+	if err != nil {
+		return fmt.Errorf("[%w]: %v", gocreem.VerifySignatureErr, err)
+	}
+	actual, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("[%w]: %v", gocreem.VerifySignatureErr, err)
+	}
+	if !hmac.Equal(expected, actual) {
+		return gocreem.VerifySignatureErr
+	}
+	return nil
+}
+
+// SignWebhook 使用当前 SecretKey 对Webhook请求体签名
+func (c *Client) SignWebhook(payload []byte) string {
+	return SignWebhookPayload(c.currentSecretKey(c.config()), payload)
+}
+
+// VerifyWebhook 使用当前 SecretKey 校验Webhook签名，
+// RotateSecretKey 的过渡期内旧密钥签名的请求同样通过
+func (c *Client) VerifyWebhook(payload []byte, signature string) error {
+	cfg := c.config()
+	err := VerifyWebhookPayload(c.currentSecretKey(cfg), payload, signature)
+	if err != nil && cfg.prevSecretKey != "" && time.Now().Before(cfg.prevSecretUntil) {
+		if VerifyWebhookPayload(cfg.prevSecretKey, payload, signature) == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// ParseWebhook 读取请求体、校验签名并解析Webhook事件
+// 文档：https://docs.creem.io/learn/webhooks/verify-webhook-requests
+func (c *Client) ParseWebhook(req *http.Request) (event *WebhookEvent, payload []byte, err error) {
+	if req == nil || req.Body == nil {
+		return nil, nil, errors.New("request is nil")
+	}
+
+	payload, err = io.ReadAll(io.LimitReader(req.Body, webhookBodyLimit))
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = c.VerifyWebhook(payload, req.Header.Get(HeaderSignature)); err != nil {
+		return nil, payload, err
+	}
+
+	event = new(WebhookEvent)
+	if err = json.Unmarshal(payload, event); err != nil {
+		return nil, payload, fmt.Errorf("[%w]: %v, bytes: %s", gocreem.UnmarshalErr, err, string(payload))
+	}
+	return event, payload, nil
+}
+
+// DecodeObject 将事件的 Object 解析到 v，如 *CheckoutSession、*Subscription
+func (e *WebhookEvent) DecodeObject(v any) error {
+	if err := json.Unmarshal(e.Object, v); err != nil {
+		return fmt.Errorf("[%w]: %v, bytes: %s", gocreem.UnmarshalErr, err, string(e.Object))
+	}
+	return nil
+}
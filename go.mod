@@ -2,4 +2,13 @@ module github.com/cloud-evan/gocreem
 
 go 1.23.0
 
-require github.com/go-pay/xlog v0.0.3
+require (
+	github.com/go-pay/xlog v0.0.3
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/kr/pretty v0.3.1 // indirect
+	github.com/rogpeppe/go-internal v1.13.1 // indirect
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
+)
@@ -0,0 +1,81 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"errors"
+)
+
+var (
+	ErrEd25519Verification = errors.New("ed25519: verification error")
+)
+
+// Implements the EdDSA family
+// Expects ed25519.PrivateKey for signing and ed25519.PublicKey for verification
+type SigningMethodEd25519 struct{}
+
+// Specific instance for EdDSA
+var (
+	SigningMethodEdDSA *SigningMethodEd25519
+)
+
+func init() {
+	SigningMethodEdDSA = &SigningMethodEd25519{}
+	RegisterSigningMethod(SigningMethodEdDSA.Alg(), func() SigningMethod {
+		return SigningMethodEdDSA
+	})
+}
+
+func (m *SigningMethodEd25519) Alg() string {
+	return "EdDSA"
+}
+
+// Implements the Verify method from SigningMethod
+// For this verify method, key must be an ed25519.PublicKey
+func (m *SigningMethodEd25519) Verify(signingString, signature string, key any) error {
+	var ed25519Key ed25519.PublicKey
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		ed25519Key = k
+	case *ed25519.PublicKey:
+		ed25519Key = *k
+	default:
+		return ErrInvalidKeyType
+	}
+
+	if len(ed25519Key) != ed25519.PublicKeySize {
+		return ErrInvalidKey
+	}
+
+	// Decode the signature
+	sig, err := DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+
+	// Verify the signature
+	if !ed25519.Verify(ed25519Key, []byte(signingString), sig) {
+		return ErrEd25519Verification
+	}
+	return nil
+}
+
+// Implements the Sign method from SigningMethod
+// For this signing method, key must be an ed25519.PrivateKey
+func (m *SigningMethodEd25519) Sign(signingString string, key any) (string, error) {
+	var ed25519Key ed25519.PrivateKey
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		ed25519Key = k
+	case *ed25519.PrivateKey:
+		ed25519Key = *k
+	default:
+		return "", ErrInvalidKeyType
+	}
+
+	if len(ed25519Key) != ed25519.PrivateKeySize {
+		return "", ErrInvalidKey
+	}
+
+	// Sign the string and return the encoded result
+	return EncodeSegment(ed25519.Sign(ed25519Key, []byte(signingString))), nil
+}
@@ -0,0 +1,206 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Conventional path for publishing a JWKS
+const JWKSPath = "/.well-known/jwks.json"
+
+var ErrKeyNotFound = errors.New("no key found for kid")
+
+// JWKSProvider supplies the current key set, e.g. a KeyRotator
+type JWKSProvider interface {
+	JWKS() *JWKS
+}
+
+// StaticJWKS is a JWKSProvider for a fixed key set
+type StaticJWKS JWKS
+
+func (s *StaticJWKS) JWKS() *JWKS {
+	return (*JWKS)(s)
+}
+
+// JWKSHandler serves the provider's key set as JSON.
+// Mount it at JWKSPath. maxAge sets the Cache-Control max-age; zero disables it.
+func JWKSHandler(provider JWKSProvider, maxAge time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		bs, err := json.Marshal(provider.JWKS())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if maxAge > 0 {
+			w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(maxAge.Seconds())))
+		}
+		_, _ = w.Write(bs)
+	})
+}
+
+// JWKSCache fetches a remote JWKS and resolves keys by kid.
+// An unknown kid triggers a refetch, at most once per MinRefreshInterval,
+// so tokens signed with a freshly rotated key are accepted without restarts.
+// Concurrent lookups share a single fetch and the lock is not held while fetching.
+type JWKSCache struct {
+	URL                string
+	HttpClient         *http.Client
+	RefreshInterval    time.Duration // Refetch the set after this long even if all kids are known
+	MinRefreshInterval time.Duration // Minimum time between two fetches
+
+	mu          sync.Mutex
+	keys        map[string]any
+	fetchedAt   time.Time
+	lastAttempt time.Time
+	inflight    *jwksFetch
+}
+
+// jwksFetch is a fetch in progress; done is closed once err is set
+type jwksFetch struct {
+	done chan struct{}
+	err  error
+}
+
+// NewJWKSCache creates a cache for the JWKS at url, refreshing hourly
+// and at most once every 30 seconds on unknown kid
+func NewJWKSCache(url string) *JWKSCache {
+	return &JWKSCache{
+		URL:                url,
+		HttpClient:         &http.Client{Timeout: 10 * time.Second},
+		RefreshInterval:    time.Hour,
+		MinRefreshInterval: 30 * time.Second,
+	}
+}
+
+// Keyfunc implements Keyfunc, resolving the key from the token's kid header.
+// Fetches triggered here are bounded only by HttpClient's timeout, use
+// KeyfuncWithContext to make them cancellable.
+func (c *JWKSCache) Keyfunc(token *Token) (any, error) {
+	return c.KeyfuncWithContext(context.Background())(token)
+}
+
+// KeyfuncWithContext returns a Keyfunc whose fetches stop waiting when ctx is done
+func (c *JWKSCache) KeyfuncWithContext(ctx context.Context) Keyfunc {
+	return func(token *Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return c.Key(ctx, kid)
+	}
+}
+
+// Key returns the public key for kid, fetching the set when needed
+func (c *JWKSCache) Key(ctx context.Context, kid string) (any, error) {
+	c.mu.Lock()
+	now := TimeFunc()
+	key, ok := c.keys[kid]
+	stale := c.RefreshInterval > 0 && now.Sub(c.fetchedAt) > c.RefreshInterval
+	if ok && !stale {
+		c.mu.Unlock()
+		return key, nil
+	}
+	var fetch *jwksFetch
+	if c.inflight != nil || now.Sub(c.lastAttempt) >= c.MinRefreshInterval {
+		fetch = c.startFetch(ctx)
+	}
+	c.mu.Unlock()
+
+	// A stale key stays usable while the set is refetched in the background
+	if fetch != nil && !ok {
+		if err := fetch.wait(ctx); err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		key, ok = c.keys[kid]
+		c.mu.Unlock()
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrKeyNotFound, kid)
+	}
+	return key, nil
+}
+
+// Refresh fetches the key set immediately, ignoring the rate limit.
+// It joins a fetch that is already in progress.
+func (c *JWKSCache) Refresh(ctx context.Context) error {
+	c.mu.Lock()
+	fetch := c.startFetch(ctx)
+	c.mu.Unlock()
+	return fetch.wait(ctx)
+}
+
+// startFetch returns the fetch in progress or starts a new one. Must hold c.mu.
+// The fetch is detached from ctx so one caller giving up does not fail the others.
+func (c *JWKSCache) startFetch(ctx context.Context) *jwksFetch {
+	if c.inflight != nil {
+		return c.inflight
+	}
+	fetch := &jwksFetch{done: make(chan struct{})}
+	c.inflight = fetch
+	c.lastAttempt = TimeFunc()
+	go func() {
+		keys, err := c.fetch(context.WithoutCancel(ctx))
+		c.mu.Lock()
+		if err == nil {
+			c.keys = keys
+			c.fetchedAt = TimeFunc()
+		}
+		c.inflight = nil
+		c.mu.Unlock()
+		fetch.err = err
+		close(fetch.done)
+	}()
+	return fetch
+}
+
+func (f *jwksFetch) wait(ctx context.Context) error {
+	select {
+	case <-f.done:
+		return f.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *JWKSCache) fetch(ctx context.Context) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	hc := c.HttpClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	res, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch jwks: StatusCode(%d) != 200", res.StatusCode)
+	}
+
+	set := new(JWKS)
+	if err = json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(set); err != nil {
+		return nil, fmt.Errorf("decode jwks: %w", err)
+	}
+	keys := make(map[string]any, len(set.Keys))
+	for i := range set.Keys {
+		// Skip keys we cannot use rather than rejecting the whole set
+		if pub, err := set.Keys[i].PublicKey(); err == nil {
+			keys[set.Keys[i].Kid] = pub
+		}
+	}
+	return keys, nil
+}
@@ -5,12 +5,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 )
 
 type Parser struct {
 	ValidMethods         []string // If populated, only these methods will be considered valid
 	UseJSONNumber        bool     // Use JSON Number format in JSON decoder
 	SkipClaimsValidation bool     // Skip claims validation during token parsing
+
+	// The fields below are usually set through NewParser options.
+	// When any of them is set, the parser validates standard claims itself.
+	Leeway            time.Duration    // Allowed clock skew for exp, iat and nbf
+	TimeFunc          func() time.Time // Clock for this parser, defaults to the package-level TimeFunc
+	ExpectedIssuer    string           // If set, "iss" is required and must match
+	ExpectedAudience  string           // If set, "aud" is required and must contain it
+	RequireExpiration bool             // If set, "exp" is required
 }
 
 // Parse, validate, and return a token.
@@ -60,7 +69,11 @@ func (p *Parser) ParseWithClaims(tokenString string, claims Claims, keyFunc Keyf
 
 	// Validate Claims
 	if !p.SkipClaimsValidation {
-		if err := token.Claims.Valid(); err != nil {
+		validate := token.Claims.Valid
+		if p.hasValidationOptions() {
+			validate = func() error { return p.validateClaims(token.Claims) }
+		}
+		if err := validate(); err != nil {
 
 			// If the Claims Valid returned an error, check if it is a validation error,
 			// If it was another error type, create a ValidationError with a generic ClaimsInvalid flag set
@@ -0,0 +1,172 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ParserOption configures a Parser created with NewParser
+type ParserOption func(*Parser)
+
+// NewParser creates a Parser with the given options.
+// A zero Parser (new(Parser)) keeps the previous behaviour: claims are checked
+// by their own Valid() against the package-level TimeFunc without leeway.
+func NewParser(options ...ParserOption) *Parser {
+	p := new(Parser)
+	for _, option := range options {
+		option(p)
+	}
+	return p
+}
+
+// WithValidMethods restricts the accepted "alg" values
+func WithValidMethods(methods []string) ParserOption {
+	return func(p *Parser) {
+		p.ValidMethods = methods
+	}
+}
+
+// WithJSONNumber decodes numbers in MapClaims as json.Number
+func WithJSONNumber() ParserOption {
+	return func(p *Parser) {
+		p.UseJSONNumber = true
+	}
+}
+
+// WithoutClaimsValidation skips exp/iat/nbf and required claim checks
+func WithoutClaimsValidation() ParserOption {
+	return func(p *Parser) {
+		p.SkipClaimsValidation = true
+	}
+}
+
+// WithLeeway tolerates clock skew when checking exp, iat and nbf
+func WithLeeway(leeway time.Duration) ParserOption {
+	return func(p *Parser) {
+		p.Leeway = leeway
+	}
+}
+
+// WithTimeFunc sets the clock used by this parser instead of the package-level TimeFunc
+func WithTimeFunc(f func() time.Time) ParserOption {
+	return func(p *Parser) {
+		p.TimeFunc = f
+	}
+}
+
+// WithIssuer requires the "iss" claim to be present and equal to iss
+func WithIssuer(iss string) ParserOption {
+	return func(p *Parser) {
+		p.ExpectedIssuer = iss
+	}
+}
+
+// WithAudience requires the "aud" claim to be present and contain aud
+func WithAudience(aud string) ParserOption {
+	return func(p *Parser) {
+		p.ExpectedAudience = aud
+	}
+}
+
+// WithExpirationRequired rejects tokens without an "exp" claim
+func WithExpirationRequired() ParserOption {
+	return func(p *Parser) {
+		p.RequireExpiration = true
+	}
+}
+
+// Standard claim checks shared by StandardClaims, MapClaims and RegisteredClaims.
+// Claims implementing it are validated by the parser when any option is set.
+type claimsVerifier interface {
+	VerifyAudience(cmp string, req bool) bool
+	VerifyExpiresAt(cmp int64, req bool) bool
+	VerifyIssuedAt(cmp int64, req bool) bool
+	VerifyIssuer(cmp string, req bool) bool
+	VerifyNotBefore(cmp int64, req bool) bool
+}
+
+// ClaimsValidator can be implemented by custom claims to add checks that run
+// after the parser's standard validation. Only used when parser options are set.
+type ClaimsValidator interface {
+	Validate() error
+}
+
+// timeValidationErrors are re-checked by the built-in Valid() against the
+// package clock without leeway; the parser has already checked them itself.
+const timeValidationErrors = ValidationErrorExpired | ValidationErrorIssuedAt | ValidationErrorNotValidYet
+
+func (p *Parser) hasValidationOptions() bool {
+	return p.Leeway != 0 || p.TimeFunc != nil || p.ExpectedIssuer != "" || p.ExpectedAudience != "" || p.RequireExpiration
+}
+
+func (p *Parser) now() time.Time {
+	if p.TimeFunc != nil {
+		return p.TimeFunc()
+	}
+	return TimeFunc()
+}
+
+// validateClaims checks exp/iat/nbf with leeway and the required claims, then
+// runs the claims' own Validate() and Valid(). Claims without the standard
+// Verify* methods are rejected rather than checked by Valid() alone, which would
+// silently ignore the parser options.
+func (p *Parser) validateClaims(claims Claims) error {
+	v, ok := claims.(claimsVerifier)
+	if !ok {
+		return NewValidationError(fmt.Sprintf("claims of type %T cannot be validated against parser options", claims), ValidationErrorClaimsInvalid)
+	}
+
+	vErr := new(ValidationError)
+	now := p.now()
+	leeway := int64(p.Leeway / time.Second)
+
+	if !v.VerifyExpiresAt(now.Unix()-leeway, p.RequireExpiration) {
+		vErr.Inner = fmt.Errorf("token is expired or missing exp")
+		vErr.Errors |= ValidationErrorExpired
+	}
+
+	if !v.VerifyIssuedAt(now.Unix()+leeway, false) {
+		vErr.Inner = fmt.Errorf("token used before issued")
+		vErr.Errors |= ValidationErrorIssuedAt
+	}
+
+	if !v.VerifyNotBefore(now.Unix()+leeway, false) {
+		vErr.Inner = fmt.Errorf("token is not valid yet")
+		vErr.Errors |= ValidationErrorNotValidYet
+	}
+
+	if p.ExpectedIssuer != "" && !v.VerifyIssuer(p.ExpectedIssuer, true) {
+		vErr.Inner = fmt.Errorf("token has invalid issuer")
+		vErr.Errors |= ValidationErrorIssuer
+	}
+
+	if p.ExpectedAudience != "" && !v.VerifyAudience(p.ExpectedAudience, true) {
+		vErr.Inner = fmt.Errorf("token has invalid audience")
+		vErr.Errors |= ValidationErrorAudience
+	}
+
+	if cv, ok := claims.(ClaimsValidator); ok {
+		if err := cv.Validate(); err != nil {
+			vErr.Inner = err
+			vErr.Errors |= ValidationErrorClaimsInvalid
+		}
+	}
+
+	if err := claims.Valid(); err != nil {
+		var e *ValidationError
+		switch {
+		case !errors.As(err, &e):
+			vErr.Inner = err
+			vErr.Errors |= ValidationErrorClaimsInvalid
+		case e.Errors&^timeValidationErrors != 0:
+			vErr.Inner = e
+			vErr.Errors |= e.Errors &^ timeValidationErrors
+		}
+	}
+
+	if vErr.valid() {
+		return nil
+	}
+	return vErr
+}
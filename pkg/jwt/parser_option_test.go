@@ -0,0 +1,119 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var testHMACKey = []byte("parser-option-test-key")
+
+func signHS256(t *testing.T, claims Claims) string {
+	t.Helper()
+	s, err := NewWithClaims(SigningMethodHS256, claims).SignedString(testHMACKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func hmacKeyfunc(*Token) (any, error) {
+	return testHMACKey, nil
+}
+
+func validationFlags(t *testing.T, err error) uint32 {
+	t.Helper()
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("expected *ValidationError, got %T: %v", err, err)
+	}
+	return ve.Errors
+}
+
+// onlyValidClaims implements Valid() but none of the Verify* methods
+type onlyValidClaims struct {
+	Issuer string `json:"iss"`
+}
+
+func (c *onlyValidClaims) Valid() error { return nil }
+
+// rejectingClaims overrides the embedded Valid() with a custom check
+type rejectingClaims struct {
+	RegisteredClaims
+	Plan string `json:"plan"`
+}
+
+func (c *rejectingClaims) Valid() error {
+	if c.Plan != "pro" {
+		return errors.New("plan not allowed")
+	}
+	return nil
+}
+
+// embeddedClaims keeps the promoted RegisteredClaims.Valid()
+type embeddedClaims struct {
+	RegisteredClaims
+	Plan string `json:"plan"`
+}
+
+func TestParserIssuerMismatch(t *testing.T) {
+	token := signHS256(t, RegisteredClaims{Issuer: "other"})
+	_, err := NewParser(WithIssuer("creem")).ParseWithClaims(token, &RegisteredClaims{}, hmacKeyfunc)
+	if validationFlags(t, err)&ValidationErrorIssuer == 0 {
+		t.Fatalf("expected issuer error, got %v", err)
+	}
+}
+
+func TestParserAudienceMismatchMapClaims(t *testing.T) {
+	token := signHS256(t, MapClaims{"aud": []string{"api"}})
+	_, err := NewParser(WithAudience("web")).Parse(token, hmacKeyfunc)
+	if validationFlags(t, err)&ValidationErrorAudience == 0 {
+		t.Fatalf("expected audience error, got %v", err)
+	}
+
+	token = signHS256(t, MapClaims{"aud": []string{"api", "web"}})
+	if _, err = NewParser(WithAudience("web")).Parse(token, hmacKeyfunc); err != nil {
+		t.Fatalf("expected audience match, got %v", err)
+	}
+}
+
+func TestParserLeewayMapClaims(t *testing.T) {
+	token := signHS256(t, MapClaims{"exp": time.Now().Add(-30 * time.Second).Unix()})
+	if _, err := NewParser(WithLeeway(time.Minute)).Parse(token, hmacKeyfunc); err != nil {
+		t.Fatalf("expected token within leeway, got %v", err)
+	}
+	_, err := NewParser(WithLeeway(10*time.Second)).Parse(token, hmacKeyfunc)
+	if validationFlags(t, err)&ValidationErrorExpired == 0 {
+		t.Fatalf("expected expired error, got %v", err)
+	}
+}
+
+func TestParserRejectsUnverifiableClaims(t *testing.T) {
+	token := signHS256(t, &onlyValidClaims{Issuer: "creem"})
+	_, err := NewParser(WithIssuer("creem")).ParseWithClaims(token, &onlyValidClaims{}, hmacKeyfunc)
+	if validationFlags(t, err)&ValidationErrorClaimsInvalid == 0 {
+		t.Fatalf("expected claims invalid error, got %v", err)
+	}
+}
+
+func TestParserRunsCustomValid(t *testing.T) {
+	token := signHS256(t, &rejectingClaims{RegisteredClaims: RegisteredClaims{Issuer: "creem"}, Plan: "free"})
+	_, err := NewParser(WithIssuer("creem")).ParseWithClaims(token, &rejectingClaims{}, hmacKeyfunc)
+	if validationFlags(t, err)&ValidationErrorClaimsInvalid == 0 {
+		t.Fatalf("expected custom Valid() error, got %v", err)
+	}
+
+	token = signHS256(t, &rejectingClaims{RegisteredClaims: RegisteredClaims{Issuer: "creem"}, Plan: "pro"})
+	if _, err = NewParser(WithIssuer("creem")).ParseWithClaims(token, &rejectingClaims{}, hmacKeyfunc); err != nil {
+		t.Fatalf("expected valid token, got %v", err)
+	}
+}
+
+func TestParserEmbeddedClaimsKeepLeeway(t *testing.T) {
+	claims := &embeddedClaims{RegisteredClaims: RegisteredClaims{ExpiresAt: NewNumericDate(time.Now().Add(-30 * time.Second))}}
+	token := signHS256(t, claims)
+	// the promoted Valid() ignores leeway, its time errors must not override the parser's checks
+	if _, err := NewParser(WithLeeway(time.Minute)).ParseWithClaims(token, &embeddedClaims{}, hmacKeyfunc); err != nil {
+		t.Fatalf("expected token within leeway, got %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package jwt
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// TimePrecision sets the precision of times in NumericDate when marshalled.
+// Defaults to whole seconds as most implementations expect.
+var TimePrecision = time.Second
+
+// NumericDate represents a JSON numeric date value, as referenced at
+// https://datatracker.ietf.org/doc/html/rfc7519#section-2
+type NumericDate struct {
+	time.Time
+}
+
+// NewNumericDate truncates t to TimePrecision
+func NewNumericDate(t time.Time) *NumericDate {
+	return &NumericDate{t.Truncate(TimePrecision)}
+}
+
+// MarshalJSON encodes the date as seconds since the epoch
+func (date NumericDate) MarshalJSON() ([]byte, error) {
+	if TimePrecision >= time.Second {
+		return []byte(strconv.FormatInt(date.Truncate(TimePrecision).Unix(), 10)), nil
+	}
+	seconds := float64(date.Truncate(TimePrecision).UnixNano()) / float64(time.Second)
+	return []byte(strconv.FormatFloat(seconds, 'f', -1, 64)), nil
+}
+
+// UnmarshalJSON accepts integer and fractional seconds
+func (date *NumericDate) UnmarshalJSON(b []byte) error {
+	var number json.Number
+	if err := json.Unmarshal(b, &number); err != nil {
+		return fmt.Errorf("could not parse NumericDate: %w", err)
+	}
+	f, err := number.Float64()
+	if err != nil {
+		return fmt.Errorf("could not convert json number value to float: %w", err)
+	}
+	sec, frac := math.Modf(f)
+	date.Time = time.Unix(int64(sec), int64(frac*1e9))
+	return nil
+}
+
+// ClaimStrings is a claim that may be a single string or an array of strings, such as "aud"
+type ClaimStrings []string
+
+func (s *ClaimStrings) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	var aud []string
+	switch v := value.(type) {
+	case string:
+		aud = append(aud, v)
+	case []any:
+		for _, vv := range v {
+			vs, ok := vv.(string)
+			if !ok {
+				return fmt.Errorf("invalid audience value %v", vv)
+			}
+			aud = append(aud, vs)
+		}
+	case nil:
+		return nil
+	default:
+		return fmt.Errorf("invalid audience type %T", v)
+	}
+	*s = aud
+	return nil
+}
+
+// MarshalJSON writes a single audience as a plain string for compatibility
+// with consumers of StandardClaims, and several as an array
+func (s ClaimStrings) MarshalJSON() ([]byte, error) {
+	if len(s) == 1 {
+		return json.Marshal(s[0])
+	}
+	return json.Marshal([]string(s))
+}
+
+// RegisteredClaims are the registered claims of RFC 7519 section 4.1 with
+// typed dates and a multi-value audience. Embed it in custom claims and parse
+// them with ParseTyped.
+type RegisteredClaims struct {
+	Issuer    string       `json:"iss,omitempty"`
+	Subject   string       `json:"sub,omitempty"`
+	Audience  ClaimStrings `json:"aud,omitempty"`
+	ExpiresAt *NumericDate `json:"exp,omitempty"`
+	NotBefore *NumericDate `json:"nbf,omitempty"`
+	IssuedAt  *NumericDate `json:"iat,omitempty"`
+	ID        string       `json:"jti,omitempty"`
+}
+
+// Validates time based claims "exp, iat, nbf" against TimeFunc.
+// Use a Parser with options for leeway and required claims.
+func (c RegisteredClaims) Valid() error {
+	vErr := new(ValidationError)
+	now := TimeFunc().Unix()
+
+	if !c.VerifyExpiresAt(now, false) {
+		vErr.Inner = fmt.Errorf("token is expired")
+		vErr.Errors |= ValidationErrorExpired
+	}
+
+	if !c.VerifyIssuedAt(now, false) {
+		vErr.Inner = fmt.Errorf("token used before issued")
+		vErr.Errors |= ValidationErrorIssuedAt
+	}
+
+	if !c.VerifyNotBefore(now, false) {
+		vErr.Inner = fmt.Errorf("token is not valid yet")
+		vErr.Errors |= ValidationErrorNotValidYet
+	}
+
+	if vErr.valid() {
+		return nil
+	}
+
+	return vErr
+}
+
+// Compares the aud claim against cmp.
+// If required is false, this method will return true if the value matches or is unset
+func (c RegisteredClaims) VerifyAudience(cmp string, req bool) bool {
+	return verifyAud(c.Audience, cmp, req)
+}
+
+// Compares the exp claim against cmp.
+// If required is false, this method will return true if the value matches or is unset
+func (c RegisteredClaims) VerifyExpiresAt(cmp int64, req bool) bool {
+	return verifyExp(c.ExpiresAt.unix(), cmp, req)
+}
+
+// Compares the iat claim against cmp.
+// If required is false, this method will return true if the value matches or is unset
+func (c RegisteredClaims) VerifyIssuedAt(cmp int64, req bool) bool {
+	return verifyIat(c.IssuedAt.unix(), cmp, req)
+}
+
+// Compares the iss claim against cmp.
+// If required is false, this method will return true if the value matches or is unset
+func (c RegisteredClaims) VerifyIssuer(cmp string, req bool) bool {
+	return verifyIss(c.Issuer, cmp, req)
+}
+
+// Compares the nbf claim against cmp.
+// If required is false, this method will return true if the value matches or is unset
+func (c RegisteredClaims) VerifyNotBefore(cmp int64, req bool) bool {
+	return verifyNbf(c.NotBefore.unix(), cmp, req)
+}
+
+// unix returns 0 for a nil date so the verify helpers treat it as unset
+func (date *NumericDate) unix() int64 {
+	if date == nil {
+		return 0
+	}
+	return date.Unix()
+}
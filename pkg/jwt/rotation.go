@@ -0,0 +1,121 @@
+package jwt
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type rotatedKey struct {
+	kid       string
+	private   any
+	public    any
+	retiredAt time.Time
+}
+
+// KeyRotator holds the active signing key plus recently retired keys.
+// Retired public keys stay in JWKS() and Keyfunc for the overlap window,
+// so tokens signed just before a rotation keep verifying until they expire.
+type KeyRotator struct {
+	method  SigningMethod
+	overlap time.Duration
+
+	mu      sync.RWMutex
+	current *rotatedKey
+	retired []*rotatedKey
+}
+
+// NewKeyRotator creates a rotator for method starting with the given key.
+// overlap should be at least the lifetime of the tokens being signed.
+func NewKeyRotator(method SigningMethod, kid string, privateKey any, overlap time.Duration) (*KeyRotator, error) {
+	key, err := newRotatedKey(kid, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyRotator{method: method, overlap: overlap, current: key}, nil
+}
+
+func newRotatedKey(kid string, privateKey any) (*rotatedKey, error) {
+	if kid == "" {
+		return nil, errors.New("kid is required")
+	}
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrInvalidKeyType, privateKey)
+	}
+	return &rotatedKey{kid: kid, private: privateKey, public: signer.Public()}, nil
+}
+
+// Rotate makes the new key active and retires the previous one for the overlap window
+func (r *KeyRotator) Rotate(kid string, privateKey any) error {
+	key, err := newRotatedKey(kid, privateKey)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.current.retiredAt = TimeFunc()
+	r.retired = append(r.pruneLocked(), r.current)
+	r.current = key
+	return nil
+}
+
+// SigningKey returns the active kid and private key
+func (r *KeyRotator) SigningKey() (kid string, key any) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.current.kid, r.current.private
+}
+
+// Sign signs claims with the active key, setting the kid header
+func (r *KeyRotator) Sign(claims Claims) (string, error) {
+	kid, key := r.SigningKey()
+	token := NewWithClaims(r.method, claims)
+	token.Header["kid"] = kid
+	return token.SignedString(key)
+}
+
+// JWKS returns the active key and all retired keys still inside the overlap window
+func (r *KeyRotator) JWKS() *JWKS {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.retired = r.pruneLocked()
+
+	set := &JWKS{Keys: make([]JWK, 0, len(r.retired)+1)}
+	for _, k := range append([]*rotatedKey{r.current}, r.retired...) {
+		if jwk, err := NewJWK(k.kid, r.method.Alg(), k.public); err == nil {
+			set.Keys = append(set.Keys, *jwk)
+		}
+	}
+	return set
+}
+
+// Keyfunc verifies tokens against the active and non-expired retired keys
+func (r *KeyRotator) Keyfunc(token *Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.current.kid == kid {
+		return r.current.public, nil
+	}
+	now := TimeFunc()
+	for _, k := range r.retired {
+		if k.kid == kid && now.Sub(k.retiredAt) <= r.overlap {
+			return k.public, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrKeyNotFound, kid)
+}
+
+func (r *KeyRotator) pruneLocked() []*rotatedKey {
+	now := TimeFunc()
+	kept := r.retired[:0]
+	for _, k := range r.retired {
+		if now.Sub(k.retiredAt) <= r.overlap {
+			kept = append(kept, k)
+		}
+	}
+	return kept
+}
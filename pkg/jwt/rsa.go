@@ -73,7 +73,7 @@ func (m *SigningMethodRSA) Verify(signingString, signature string, key any) erro
 	h.Write([]byte(signingString))
 
 	// Verify the signature
-	if err = rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, h.Sum(nil), sig); err != nil {
+	if err = rsa.VerifyPKCS1v15(rsaKey, m.Hash, h.Sum(nil), sig); err != nil {
 		return fmt.Errorf("[%w]: %v", gocreem.VerifySignatureErr, err)
 	}
 	return nil
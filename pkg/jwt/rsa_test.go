@@ -0,0 +1,26 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+)
+
+func TestRSASignAndVerify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const signingString = "eyJhbGciOiJSUzM4NCJ9.eyJpc3MiOiJjcmVlbSJ9"
+	for _, m := range []*SigningMethodRSA{SigningMethodRS256, SigningMethodRS384, SigningMethodRS512} {
+		t.Run(m.Alg(), func(t *testing.T) {
+			sig, err := m.Sign(signingString, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = m.Verify(signingString, sig, &key.PublicKey); err != nil {
+				t.Fatalf("expected valid %s signature, got %v", m.Alg(), err)
+			}
+		})
+	}
+}
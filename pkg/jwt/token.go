@@ -10,6 +10,7 @@ import (
 // TimeFunc provides the current time when parsing token to validate "exp" claim (expiration time).
 // You can override it to use another time value.  This is useful for testing or if your
 // server uses a different time zone than your tokens.
+// Parsers created with WithTimeFunc use their own clock instead.
 var TimeFunc = time.Now
 
 // Parse methods use this callback function to supply
@@ -0,0 +1,54 @@
+package jwt
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TypedToken is a Token whose claims are already of type T,
+// so callers do not need a type assertion on Token.Claims
+type TypedToken[T Claims] struct {
+	*Token
+	Claims T
+}
+
+// ParseTyped parses, validates and returns a token with claims of type T.
+// T must be a pointer to a struct (e.g. *RegisteredClaims or a custom struct
+// embedding it) or MapClaims.
+//
+//	token, err := jwt.ParseTyped[*MyClaims](tokenString, keyFunc, jwt.WithLeeway(time.Minute))
+//	token.Claims.Foo
+func ParseTyped[T Claims](tokenString string, keyFunc Keyfunc, options ...ParserOption) (*TypedToken[T], error) {
+	return ParseTypedWithParser[T](NewParser(options...), tokenString, keyFunc)
+}
+
+// ParseTypedWithParser is like ParseTyped but reuses an existing Parser
+func ParseTypedWithParser[T Claims](p *Parser, tokenString string, keyFunc Keyfunc) (*TypedToken[T], error) {
+	claims, err := newClaims[T]()
+	if err != nil {
+		return nil, err
+	}
+	token, err := p.ParseWithClaims(tokenString, claims, keyFunc)
+	if token == nil {
+		return nil, err
+	}
+	typed := &TypedToken[T]{Token: token, Claims: claims}
+	// MapClaims are replaced by the decoder, pick up the decoded value
+	if c, ok := token.Claims.(T); ok {
+		typed.Claims = c
+	}
+	return typed, err
+}
+
+// newClaims allocates an empty T the decoder can fill in
+func newClaims[T Claims]() (T, error) {
+	var zero T
+	rt := reflect.TypeOf(&zero).Elem()
+	switch {
+	case rt.Kind() == reflect.Pointer && rt.Elem().Kind() == reflect.Struct:
+		return reflect.New(rt.Elem()).Interface().(T), nil
+	case rt == reflect.TypeOf(MapClaims{}):
+		return any(MapClaims{}).(T), nil
+	}
+	return zero, fmt.Errorf("jwt: unsupported claims type %v, use a struct pointer or MapClaims", rt)
+}
@@ -63,6 +63,12 @@ func (c *Client) SetTimeout(timeout time.Duration) (client *Client) {
 	return c
 }
 
+// Clone returns a copy that shares the underlying http.Client and its connection pool
+func (c *Client) Clone() (client *Client) {
+	cp := *c
+	return &cp
+}
+
 // set body size (MB), default is 10MB
 func (c *Client) SetBodySize(sizeMB int) (client *Client) {
 	c.bodySize = sizeMB
@@ -2,6 +2,7 @@ package xhttp
 
 import (
 	"encoding/json"
+	"errors"
 	"strings"
 )
 
@@ -21,6 +22,9 @@ const (
 	TypeMultipartFormData = "multipart-form-data"
 )
 
+// ErrResponseTooLarge is returned when the response body exceeds the client's body size limit
+var ErrResponseTooLarge = errors.New("response body too large")
+
 var (
 	_ReqContentTypeMap = map[string]string{
 		TypeJSON:              "application/json",
@@ -139,8 +139,56 @@ func (r *Request) SendString(encodeStr string) (client *Request) {
 }
 
 func (r *Request) EndBytes(ctx context.Context) (res *http.Response, bs []byte, err error) {
+	res, err = r.do(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+	bs, err = r.readBody(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, bs, nil
+}
+
+// EndStream sends the request and passes the body of a 2xx response to fn without buffering it,
+// so large responses can be decoded incrementally. Other responses are read into bs with the body size limit.
+func (r *Request) EndStream(ctx context.Context, fn func(res *http.Response, body io.Reader) error) (res *http.Response, bs []byte, err error) {
+	res, err = r.do(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= http.StatusOK && res.StatusCode < http.StatusMultipleChoices {
+		if err = fn(res, res.Body); err != nil {
+			return res, nil, err
+		}
+		return res, nil, nil
+	}
+	bs, err = r.readBody(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, bs, nil
+}
+
+// readBody reads at most bodySize MB, returning ErrResponseTooLarge instead of a truncated body
+func (r *Request) readBody(body io.Reader) ([]byte, error) {
+	limit := int64(r.client.bodySize << 20) // default 10MB change the size you want
+	bs, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(bs)) > limit {
+		return nil, fmt.Errorf("%w: limit is %dMB", ErrResponseTooLarge, r.client.bodySize)
+	}
+	return bs, nil
+}
+
+// do builds and sends the request, the caller must close res.Body
+func (r *Request) do(ctx context.Context) (res *http.Response, err error) {
 	if r.err != nil {
-		return nil, nil, r.err
+		return nil, r.err
 	}
 	var (
 		body io.Reader
@@ -171,7 +219,7 @@ func (r *Request) EndBytes(ctx context.Context) (res *http.Response, bs []byte,
 				if file, ok := v.(*gocreem.File); ok {
 					fw, e := bw.CreateFormFile(k, file.Name)
 					if e != nil {
-						return nil, nil, e
+						return nil, e
 					}
 					_, _ = fw.Write(file.Content)
 					continue
@@ -191,28 +239,19 @@ func (r *Request) EndBytes(ctx context.Context) (res *http.Response, bs []byte,
 				body = strings.NewReader(r.formString)
 			}
 		default:
-			return nil, nil, errors.New("Request type Error ")
+			return nil, errors.New("Request type Error ")
 		}
 	default:
-		return nil, nil, errors.New("Only support GET and POST and PUT and DELETE ")
+		return nil, errors.New("Only support GET and POST and PUT and DELETE ")
 	}
 
 	// request
 	req, err := http.NewRequestWithContext(ctx, r.method, r.url, body)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	req.Header = r.Header
-	res, err = r.client.HttpClient.Do(req)
-	if err != nil {
-		return nil, nil, err
-	}
-	defer res.Body.Close()
-	bs, err = io.ReadAll(io.LimitReader(res.Body, int64(r.client.bodySize<<20))) // default 10MB change the size you want
-	if err != nil {
-		return nil, nil, err
-	}
-	return res, bs, nil
+	return r.client.HttpClient.Do(req)
 }
 
 func (r *Request) EndStruct(ctx context.Context, v any) (res *http.Response, err error) {